@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+// accessLogTimeFormat - формат времени в Combined Log Format.
+const accessLogTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// openAccessLog - функция для открытия журнала доступа. Если path пустой, журнал пишется в stdout.
+func openAccessLog(path string) (io.Writer, error) {
+	if path == "" {
+		return os.Stdout, nil
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при открытии журнала доступа %s: %v", path, err)
+	}
+	return file, nil
+}
+
+// accessLogMiddleware - middleware для записи каждого запроса в журнал доступа в Combined Log Format.
+// После стандартных полей добавляется время обработки запроса в миллисекундах.
+func accessLogMiddleware(out io.Writer) func(http.Handler) http.Handler {
+	// log.Logger сериализует запись, поэтому строки параллельных запросов не перемешиваются.
+	logger := log.New(out, "", 0)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := &responseRecorder{ResponseWriter: w}
+			next.ServeHTTP(recorder, r)
+
+			status := recorder.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			logger.Printf("%s - %s [%s] %q %d %d %q %q %.3f",
+				clientIP(r), orDash(accessLogUser(r)), start.Format(accessLogTimeFormat),
+				r.Method+" "+r.RequestURI+" "+r.Proto, status, recorder.bytes,
+				orDash(r.Referer()), orDash(r.UserAgent()), float64(time.Since(start).Microseconds())/1000)
+		})
+	}
+}
+
+// accessLogUser - функция для получения логина HTTP Basic (пустой, если он не передан).
+func accessLogUser(r *http.Request) string {
+	user, _, _ := r.BasicAuth()
+	return user
+}
+
+// orDash - функция для замены пустого поля журнала доступа на "-", как принято в Combined Log Format.
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// ErrorResponse - структура для передачи ошибки в формате JSON.
+type ErrorResponse struct {
+	Error string `json:"error"` // Error - текст ошибки.
+
+	RequestID string `json:"requestId,omitempty"` // RequestID - идентификатор запроса для поиска в логах.
+}
+
+// writeJSON - вспомогательная функция для отправки ответа в формате JSON.
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		config.Logger.Error("Ошибка при кодировании данных в JSON:", err)
+	}
+}
+
+// writeJSONError - вспомогательная функция для отправки ошибки в формате JSON.
+func writeJSONError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, ErrorResponse{Error: msg})
+}
+
+// Варианты обертки ответа JSON API.
+const (
+	envelopeFlat    = "flat"
+	envelopeWrapped = "wrapped"
+)
+
+// ResponseMeta - структура со служебными сведениями об ответе JSON API.
+type ResponseMeta struct {
+	Total        int         `json:"total"`                  // Total - количество элементов в ответе.
+	Page         int         `json:"page"`                   // Page - номер страницы.
+	Elapsed      string      `json:"elapsed"`                // Elapsed - время обработки запроса.
+	Truncated    bool        `json:"truncated"`              // Truncated - подсчет размера остановлен по лимиту.
+	ScannedBytes float64     `json:"scannedBytes,omitempty"` // ScannedBytes - размер, подсчитанный при сканировании (при Truncated - до остановки).
+	Errors       []string    `json:"errors,omitempty"`       // Errors - предупреждения, возникшие при сканировании.
+	Skipped      SkippedMeta `json:"skipped"`                // Skipped - элементы, скрытые правилами исключения.
+	Degraded     bool        `json:"degraded,omitempty"`     // Degraded - ответ сформирован в режиме деградации из кэша.
+	Reason       string      `json:"reason,omitempty"`       // Reason - причина перехода в режим деградации.
+}
+
+// SkippedMeta - структура со сведениями о скрытых элементах.
+type SkippedMeta struct {
+	Count int     `json:"count"` // Count - количество скрытых элементов.
+	Bytes float64 `json:"bytes"` // Bytes - суммарный размер скрытых файлов.
+}
+
+// WrappedResponse - структура ответа JSON API в обертке с данными и служебными сведениями.
+type WrappedResponse struct {
+	Data interface{}  `json:"data"` // Data - данные ответа.
+	Meta ResponseMeta `json:"meta"` // Meta - служебные сведения.
+}
+
+// parseEnvelope - функция для получения вида обертки из параметра envelope (по умолчанию wrapped).
+func parseEnvelope(r *http.Request) (string, error) {
+	switch envelope := r.URL.Query().Get("envelope"); envelope {
+	case "":
+		return envelopeWrapped, nil
+	case envelopeFlat, envelopeWrapped:
+		return envelope, nil
+	default:
+		return "", errors.New("неправильно указан envelope. Используйте 'flat' или 'wrapped'")
+	}
+}
+
+// jsonResponse - вспомогательная функция для отправки данных без обертки (flat) или вместе с meta (wrapped).
+func jsonResponse(w http.ResponseWriter, data interface{}, meta ResponseMeta, envelope string) {
+	if envelope == envelopeFlat {
+		writeJSON(w, http.StatusOK, data)
+		return
+	}
+	writeJSON(w, http.StatusOK, WrappedResponse{Data: data, Meta: meta})
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"embed"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// webFS - встроенные шаблоны и статические файлы, чтобы сервер не зависел от рабочей директории.
+// bundle.js собирается webpack в web/static и должен существовать к моменту go build.
+//
+//go:embed web/templates web/static
+var webFS embed.FS
+
+// staticHandler - функция для раздачи встроенных статических файлов из web/static.
+// В режиме --dev файлы читаются с диска, чтобы правки были видны без перезапуска.
+func staticHandler() http.Handler {
+	if config.Dev {
+		return http.FileServer(http.Dir("web/static"))
+	}
+	static, err := fs.Sub(webFS, "web/static")
+	if err != nil {
+		// fs.Sub возвращает ошибку только для некорректного пути, который здесь задан константой.
+		panic(err)
+	}
+	return http.FileServer(http.FS(static))
+}
+
+// templateCache - структура для хранения разобранного шаблона. В обычном режиме шаблон разбирается
+// из встроенных файлов один раз, в режиме --dev - с диска заново при каждом изменении файла.
+type templateCache struct {
+	path    string // path - путь к шаблону относительно корня проекта.
+	mu      sync.RWMutex
+	tmpl    *template.Template
+	lastMod time.Time // lastMod - время изменения файла при последнем разборе (только в --dev).
+}
+
+// Шаблоны страниц.
+var (
+	indexTemplate  = &templateCache{path: "web/templates/index.html"}
+	streamTemplate = &templateCache{path: "web/templates/stream.html"}
+)
+
+// Get - метод для получения шаблона, при необходимости разбирает его заново.
+func (c *templateCache) Get() (*template.Template, error) {
+	if config.Dev {
+		return c.getDev()
+	}
+
+	c.mu.RLock()
+	tmpl := c.tmpl
+	c.mu.RUnlock()
+	if tmpl != nil {
+		return tmpl, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tmpl == nil {
+		parsed, err := template.ParseFS(webFS, c.path)
+		if err != nil {
+			return nil, err
+		}
+		c.tmpl = parsed
+	}
+	return c.tmpl, nil
+}
+
+// getDev - метод для получения шаблона с диска с повторным разбором при изменении файла.
+func (c *templateCache) getDev() (*template.Template, error) {
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	tmpl, lastMod := c.tmpl, c.lastMod
+	c.mu.RUnlock()
+	if tmpl != nil && info.ModTime().Equal(lastMod) {
+		return tmpl, nil
+	}
+
+	parsed, err := template.ParseFiles(c.path)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.tmpl, c.lastMod = parsed, info.ModTime()
+	c.mu.Unlock()
+	return parsed, nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"html/template"
+	"os"
+	"os/signal"
+	"regexp"
+	"sync"
+	"syscall"
+)
+
+// bannerStore - объявление, выводимое над страницей. Хранится отдельно от config,
+// так как может быть заменено по SIGHUP во время работы сервера.
+type bannerStore struct {
+	mu   sync.RWMutex
+	text string
+}
+
+// banner - текущее объявление сервера.
+var banner bannerStore
+
+// Get - метод для получения текста объявления.
+func (b *bannerStore) Get() string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.text
+}
+
+// Set - метод для замены текста объявления.
+func (b *bannerStore) Set(text string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.text = text
+}
+
+var (
+	bannerLink   = regexp.MustCompile(`\[([^\]]+)\]\(((?:https?://|/)[^)\s"]*)\)`)
+	bannerBold   = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	bannerItalic = regexp.MustCompile(`\*([^*]+)\*`)
+)
+
+// renderBanner - функция для перевода объявления в HTML. Текст экранируется целиком, после чего
+// поддерживаются только **жирный**, *курсив* и [ссылки](url) с адресами http(s) или от корня сайта.
+func renderBanner(text string) template.HTML {
+	if text == "" {
+		return ""
+	}
+	escaped := html.EscapeString(text)
+	escaped = bannerLink.ReplaceAllString(escaped, `<a class="link" href="$2">$1</a>`)
+	escaped = bannerBold.ReplaceAllString(escaped, `<strong>$1</strong>`)
+	escaped = bannerItalic.ReplaceAllString(escaped, `<em>$1</em>`)
+	return template.HTML(escaped)
+}
+
+// watchBannerReload - функция для перечитывания объявления по SIGHUP. Если сервер запущен
+// с --config, объявление берется из файла настроек, иначе остается заданным при запуске.
+func watchBannerReload(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+		}
+
+		if config.ConfigFile == "" {
+			config.Logger.Info("Получен SIGHUP, но файл настроек не задан: объявление не изменено")
+			continue
+		}
+		cfg, err := readConfigFile(config.ConfigFile)
+		if err != nil {
+			config.Logger.Error(fmt.Sprintf("Ошибка при перечитывании настроек по SIGHUP: %v", err))
+			continue
+		}
+		banner.Set(cfg.Banner)
+		config.Logger.Info(fmt.Sprintf("Объявление перечитано из %s", config.ConfigFile))
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// bookmarkIcons - допустимые значки закладок. Первый используется по умолчанию.
+var bookmarkIcons = []string{"folder", "home", "star", "disk", "archive"}
+
+// Bookmark - структура для хранения закладки на директорию.
+type Bookmark struct {
+	ID    string `json:"id"`    // ID - идентификатор закладки.
+	Path  string `json:"path"`  // Path - путь к директории.
+	Label string `json:"label"` // Label - подпись закладки.
+	Icon  string `json:"icon"`  // Icon - имя значка.
+}
+
+// BookmarkStore - хранилище закладок, сохраняемое в JSON-файл.
+type BookmarkStore struct {
+	mu        sync.Mutex
+	file      string     // file - путь к файлу с закладками.
+	bookmarks []Bookmark // bookmarks - текущий список закладок.
+}
+
+// bookmarks - хранилище закладок сервера, nil если закладки не удалось загрузить.
+var bookmarks *BookmarkStore
+
+// loadBookmarks - функция для загрузки закладок из файла в домашней директории пользователя.
+func loadBookmarks() (*BookmarkStore, error) {
+	file, err := defaultBookmarksFile()
+	if err != nil {
+		return nil, err
+	}
+	return NewBookmarkStore(file)
+}
+
+// defaultBookmarksFile - функция для получения пути к файлу закладок в домашней директории.
+func defaultBookmarksFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".filesystem", "bookmarks.json"), nil
+}
+
+// NewBookmarkStore - функция для создания хранилища и загрузки закладок из файла.
+func NewBookmarkStore(file string) (*BookmarkStore, error) {
+	store := &BookmarkStore{file: file}
+
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &store.bookmarks); err != nil {
+		return nil, fmt.Errorf("ошибка чтения файла закладок %s: %w", file, err)
+	}
+	return store, nil
+}
+
+// List - метод для получения закладок, отсортированных по подписи. Для отключенного
+// хранилища (nil) возвращает пустой список.
+func (s *BookmarkStore) List() []Bookmark {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]Bookmark, len(s.bookmarks))
+	copy(result, s.bookmarks)
+	sort.Slice(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Label) < strings.ToLower(result[j].Label)
+	})
+	return result
+}
+
+// Add - метод для добавления закладки. Идентификатор генерируется автоматически.
+func (s *BookmarkStore) Add(bookmark Bookmark) (Bookmark, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return Bookmark{}, err
+	}
+	bookmark.ID = hex.EncodeToString(id)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bookmarks = append(s.bookmarks, bookmark)
+	if err := s.save(); err != nil {
+		s.bookmarks = s.bookmarks[:len(s.bookmarks)-1]
+		return Bookmark{}, err
+	}
+	return bookmark, nil
+}
+
+// Delete - метод для удаления закладки по идентификатору. Возвращает false, если закладки нет.
+func (s *BookmarkStore) Delete(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, bookmark := range s.bookmarks {
+		if bookmark.ID != id {
+			continue
+		}
+		prev := s.bookmarks
+		s.bookmarks = append(append([]Bookmark{}, prev[:i]...), prev[i+1:]...)
+		if err := s.save(); err != nil {
+			s.bookmarks = prev
+			return false, err
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// save - метод для записи закладок в файл. Вызывается под мьютексом.
+func (s *BookmarkStore) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.file), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s.bookmarks, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.file, data, 0o600)
+}
+
+// validBookmarkIcon - функция для проверки имени значка.
+func validBookmarkIcon(icon string) bool {
+	for _, name := range bookmarkIcons {
+		if icon == name {
+			return true
+		}
+	}
+	return false
+}
+
+// handleBookmarks - функция-обработчик для получения списка и создания закладок.
+func handleBookmarks(w http.ResponseWriter, r *http.Request) {
+	if bookmarks == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "закладки отключены: не удалось загрузить файл закладок")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, bookmarks.List())
+	case http.MethodPost:
+		var bookmark Bookmark
+		if err := json.NewDecoder(r.Body).Decode(&bookmark); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "неверный формат закладки")
+			return
+		}
+		if bookmark.Path == "" || bookmark.Label == "" {
+			writeJSONError(w, http.StatusBadRequest, "не указан путь(path) или подпись(label) закладки")
+			return
+		}
+		if bookmark.Icon == "" {
+			bookmark.Icon = bookmarkIcons[0]
+		}
+		if !validBookmarkIcon(bookmark.Icon) {
+			writeJSONError(w, http.StatusBadRequest, "неизвестный значок. Используйте: "+strings.Join(bookmarkIcons, ", "))
+			return
+		}
+		bookmark.Path = filepath.Clean(bookmark.Path)
+
+		created, err := bookmarks.Add(bookmark)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, fmt.Sprintf("ошибка сохранения закладки: %v", err))
+			return
+		}
+		writeJSON(w, http.StatusCreated, created)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeJSONError(w, http.StatusMethodNotAllowed, "метод не поддерживается")
+	}
+}
+
+// handleBookmark - функция-обработчик для удаления закладки по пути /api/bookmarks/{id}.
+func handleBookmark(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		w.Header().Set("Allow", http.MethodDelete)
+		writeJSONError(w, http.StatusMethodNotAllowed, "метод не поддерживается")
+		return
+	}
+
+	if bookmarks == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "закладки отключены: не удалось загрузить файл закладок")
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/api/bookmarks/")
+	deleted, err := bookmarks.Delete(id)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, fmt.Sprintf("ошибка удаления закладки: %v", err))
+		return
+	}
+	if !deleted {
+		writeJSONError(w, http.StatusNotFound, "закладка не найдена")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// BreadcrumbItem - структура одного звена навигационной цепочки.
+type BreadcrumbItem struct {
+	Label string // Label - имя директории.
+	Path  string // Path - полный путь до директории.
+}
+
+// buildBreadcrumbs - функция для разбиения пути на цепочку родительских директорий, от корня до dirPath.
+func buildBreadcrumbs(dirPath string) []BreadcrumbItem {
+	if dirPath == "" {
+		return nil
+	}
+	clean := filepath.Clean(dirPath)
+	volume := filepath.VolumeName(clean)
+	rest := clean[len(volume):]
+
+	var items []BreadcrumbItem
+	current := volume
+	if strings.HasPrefix(rest, string(filepath.Separator)) {
+		current += string(filepath.Separator)
+		items = append(items, BreadcrumbItem{Label: current, Path: current})
+	}
+	for _, part := range strings.Split(rest, string(filepath.Separator)) {
+		if part == "" {
+			continue
+		}
+		current = filepath.Join(current, part)
+		items = append(items, BreadcrumbItem{Label: part, Path: current})
+	}
+	return items
+}
+
+// parentPath - функция для получения родительской директории. В корне файловой системы возвращает пустую строку.
+func parentPath(dirPath string) string {
+	if dirPath == "" {
+		return ""
+	}
+	clean := filepath.Clean(dirPath)
+	parent := filepath.Dir(clean)
+	if parent == clean {
+		return ""
+	}
+	return parent
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	filesystem "filesystem/file_system"
+)
+
+// cachedDirs - содержимое файла с результатами сканирования, сгруппированное по родительской директории.
+var cachedDirs map[string][]filesystem.FileInfo
+
+// loadCacheFile - функция для чтения файла с результатами сканирования.
+// Файл содержит JSON-массив FileInfo с размерами в байтах и полными путями в поле Path.
+func loadCacheFile(path string) (map[string][]filesystem.FileInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []filesystem.FileInfo
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("ошибка разбора файла %s: %w", path, err)
+	}
+
+	dirs := make(map[string][]filesystem.FileInfo)
+	for _, entry := range entries {
+		parent := filepath.Dir(filepath.Clean(entry.Path))
+		entry.Name = filepath.Base(entry.Path)
+		dirs[parent] = append(dirs[parent], entry)
+	}
+	return dirs, nil
+}
+
+// listDirFromCache - функция для получения содержимого директории из файла с результатами сканирования.
+// Возвращает список и суммарный размер директории. Поддиректории, закрытые --forbidden, в список
+// не попадают, как и при сканировании, но учитываются в размере.
+func listDirFromCache(dirPath string) ([]filesystem.FileInfo, float64, error) {
+	entries, ok := cachedDirs[filepath.Clean(dirPath)]
+	if !ok {
+		return nil, 0, fmt.Errorf("директория %s отсутствует в файле с результатами сканирования", dirPath)
+	}
+
+	fileList := make([]filesystem.FileInfo, 0, len(entries))
+	var totalSize float64
+	for _, entry := range entries {
+		totalSize += entry.Size
+		if entry.IsDir && isForbiddenPath(entry.Path) {
+			continue
+		}
+		fileList = append(fileList, entry)
+	}
+	return fileList, totalSize, nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"net/url"
+
+	filesystem "filesystem/file_system"
+)
+
+// Column - структура заголовка столбца таблицы.
+type Column struct {
+	Name        string // Name - подпись столбца.
+	SortURL     string // SortURL - ссылка для сортировки по столбцу, пустая для несортируемых столбцов.
+	CurrentSort bool   // CurrentSort - список сейчас отсортирован по этому столбцу.
+	Direction   string // Direction - текущее направление сортировки (asc или desc).
+}
+
+// tableColumn - описание столбца таблицы.
+type tableColumn struct {
+	key     string // key - ключ подписи в каталоге сообщений.
+	sortKey string // sortKey - ключ сортировки по столбцу, пустой для несортируемых столбцов.
+}
+
+// tableColumns - столбцы таблицы в порядке вывода.
+var tableColumns = []tableColumn{
+	{key: "table.name", sortKey: filesystem.SortByName},
+	{key: "table.size", sortKey: filesystem.SortBySize},
+	{key: "table.mtime", sortKey: filesystem.SortByMtime},
+	{key: "table.permissions"},
+	{key: "table.type"},
+	{key: "table.path"},
+}
+
+// buildColumns - функция для формирования заголовков столбцов со ссылками на пересортировку.
+// Для текущего столбца направление меняется на противоположное, глубина depth сохраняется, если задана.
+func buildColumns(dirPath string, sortOpts filesystem.SortOptions, depth, lang string) []Column {
+	columns := make([]Column, 0, len(tableColumns))
+	for _, col := range tableColumns {
+		column := Column{Name: translate(lang, col.key)}
+		if col.sortKey != "" && dirPath != "" {
+			column.CurrentSort = col.sortKey == sortOpts.Key
+			next := "asc"
+			if column.CurrentSort {
+				column.Direction = sortOpts.Order
+				if sortOpts.Order == "asc" {
+					next = "desc"
+				}
+			}
+			query := url.Values{}
+			query.Set("root", dirPath)
+			query.Set("by", col.sortKey)
+			query.Set("sort", next)
+			if sortOpts.DirsFirst {
+				query.Set("dirsFirst", "true")
+			}
+			if depth != "" {
+				query.Set("depth", depth)
+			}
+			column.SortURL = "/?" + query.Encode()
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
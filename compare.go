@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+
+	filesystem "filesystem/file_system"
+)
+
+// CompareResponse - структура ответа сравнения двух директорий.
+type CompareResponse struct {
+	Added   []filesystem.FileInfo `json:"added"`   // Added - элементы, которые есть только в b.
+	Removed []filesystem.FileInfo `json:"removed"` // Removed - элементы, которые есть только в a.
+	Changed []filesystem.FileInfo `json:"changed"` // Changed - элементы b, у которых отличается размер или время изменения.
+}
+
+// handleCompareDirs - функция-обработчик для сравнения содержимого директорий a и b на один уровень
+// вглубь, например резервной копии и рабочей директории. Элементы сопоставляются по имени.
+func handleCompareDirs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("a") == "" || query.Get("b") == "" {
+		writeJSONError(w, http.StatusBadRequest, "не указаны директории a и b")
+		return
+	}
+	lang := resolveLang(r)
+	binary, err := parseBinary(r, lang)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	lists := make([][]filesystem.FileInfo, 2)
+	for i, param := range []string{"a", "b"} {
+		dirPath := filepath.Clean(query.Get(param))
+		if err := checkPath(dirPath, lang); err != nil {
+			writeJSONError(w, http.StatusForbidden, err.Error())
+			return
+		}
+
+		opts := config.requestScanOptions(r)
+		opts.ListDepth = 1
+		fileList, _, err := filesystem.ListDirWithSkipped(r.Context(), dirPath, opts)
+		var partial *filesystem.PartialError
+		if errors.As(err, &partial) {
+			err = nil
+		}
+		if r.Context().Err() != nil {
+			// Клиент отключился или истекло время запроса.
+			return
+		}
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		lists[i] = fileList
+	}
+
+	diff := filesystem.CompareDirs(lists[0], lists[1])
+	convertFileListSizes(diff.Added, binary, lang)
+	convertFileListSizes(diff.Removed, binary, lang)
+	convertFileListSizes(diff.Changed, binary, lang)
+	writeJSON(w, http.StatusOK, CompareResponse{Added: diff.Added, Removed: diff.Removed, Changed: diff.Changed})
+}
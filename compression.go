@@ -0,0 +1,47 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	filesystem "filesystem/file_system"
+)
+
+// defaultSamplePct - доля файлов в процентах, попадающих в выборку по умолчанию.
+const defaultSamplePct = 5
+
+// handleCompressionEstimate - функция-обработчик для оценки сжатия содержимого директории.
+func handleCompressionEstimate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeJSONError(w, http.StatusMethodNotAllowed, "метод не поддерживается")
+		return
+	}
+
+	dirPath := r.URL.Query().Get("root")
+	if dirPath == "" {
+		writeJSONError(w, http.StatusBadRequest, "не указана директория(root)")
+		return
+	}
+	if err := checkPath(dirPath, resolveLang(r)); err != nil {
+		writeJSONError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	samplePct := float64(defaultSamplePct)
+	if value := r.URL.Query().Get("sample-pct"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed <= 0 || parsed > 100 {
+			writeJSONError(w, http.StatusBadRequest, "sample-pct должен быть числом от 0 до 100")
+			return
+		}
+		samplePct = parsed
+	}
+
+	estimate, err := filesystem.EstimateCompression(dirPath, samplePct, requestLogger(r))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, estimate)
+}
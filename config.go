@@ -0,0 +1,462 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	filesystem "filesystem/file_system"
+)
+
+// Config - структура для хранения настроек сервера, переданных флагами командной строки.
+type Config struct {
+	ListDepth int `json:"listDepth"` // ListDepth - количество выводимых уровней вложенности (0 - без ограничения).
+	SizeDepth int `json:"sizeDepth"` // SizeDepth - глубина обхода при вычислении размера директорий (0 - без ограничения).
+
+	FollowSymlinks bool  `json:"followSymlinks"` // FollowSymlinks - учитывать содержимое директорий, на которые указывают символические ссылки.
+	MaxGoroutines  int   `json:"maxGoroutines"`  // MaxGoroutines - число горутин, после которого сканирование идет последовательно.
+	ScanWorkers    int   `json:"scanWorkers"`    // ScanWorkers - число горутин, обрабатывающих элементы одной директории.
+	MaxScanSize    int64 `json:"maxScanSize"`    // MaxScanSize - размер в байтах, после которого подсчет размера останавливается.
+	MaxZipBytes    int64 `json:"maxZipBytes"`    // MaxZipBytes - наибольший общий размер файлов директории, скачиваемой архивом.
+	PreviewBytes   int64 `json:"previewBytes"`   // PreviewBytes - сколько байт файла выводится при предпросмотре.
+	PartialOnError bool  `json:"partialOnError"` // PartialOnError - выводить прочитанные элементы, если часть директорий недоступна.
+
+	InlineTypes []string `json:"inlineTypes"` // InlineTypes - типы содержимого (image/* - вся группа), которые /download отдает для просмотра в браузере.
+
+	AllowUnicodeFilenames bool `json:"allowUnicodeFilenames"` // AllowUnicodeFilenames - оставлять символы не из ASCII в имени архива из параметра filename.
+
+	SearchMaxResults int `json:"searchMaxResults"` // SearchMaxResults - наибольшее количество результатов поиска по именам.
+
+	DupWorkers     int   `json:"dupWorkers"`     // DupWorkers - число горутин, считающих SHA-256 при поиске одинаковых файлов.
+	DupMaxFileSize int64 `json:"dupMaxFileSize"` // DupMaxFileSize - файлы больше этого размера не проверяются на совпадение (0 - без ограничения).
+
+	FastSize bool `json:"fastSize"` // FastSize - оценивать размер директорий через du для быстрого первого вывода.
+
+	SkipNetworkMounts bool `json:"skipNetworkMounts"` // SkipNetworkMounts - не сканировать сетевые файловые системы (NFS, CIFS, SSHFS и т.п.).
+
+	DefaultRoot string `json:"defaultRoot"` // DefaultRoot - директория, открываемая при заходе на главную страницу без параметров.
+
+	CacheFile string `json:"cacheFile"` // CacheFile - файл с результатами сканирования; если задан, сервер работает только на чтение из него.
+
+	PrewarmPaths []string      `json:"prewarmPaths"` // PrewarmPaths - директории, сканируемые в фоне при запуске сервера.
+	PrewarmTTL   time.Duration `json:"prewarmTTL"`   // PrewarmTTL - время, в течение которого прогретые директории отдаются без повторного сканирования.
+
+	Lang string `json:"lang"` // Lang - язык интерфейса (ru, en, de).
+
+	SortLocale string `json:"sortLocale"` // SortLocale - правило сравнения имен при сортировке по имени: os или unicode.
+
+	GitBlame bool `json:"gitBlame"` // GitBlame - выводить автора и время последнего коммита для файлов в git-репозитории.
+
+	MaxCacheMemory uint64 `json:"maxCacheMemory"` // MaxCacheMemory - объем кучи в байтах, после которого кэш сканирования сокращается.
+
+	LeakMonitorInterval  time.Duration `json:"leakMonitorInterval"`  // LeakMonitorInterval - период проверки роста кучи (0 - проверка отключена).
+	LeakMonitorThreshold float64       `json:"leakMonitorThreshold"` // LeakMonitorThreshold - рост кучи в байтах в минуту, после которого выводится предупреждение.
+
+	Forbidden []string `json:"forbidden"` // Forbidden - директории, просмотр которых запрещен вместе с содержимым.
+	RootJail  string   `json:"rootJail"`  // RootJail - директория, за пределы которой просмотр запрещен (пустая - без ограничения).
+
+	AuthUser     string `json:"authUser"`     // AuthUser - логин для входа на сервер (HTTP Basic).
+	AuthPassword string `json:"authPassword"` // AuthPassword - пароль для входа на сервер.
+
+	APIKey string `json:"apiKey"` // APIKey - ключ для доступа к обработчикам /api/ (пустой - без ключа).
+
+	CORSOrigins []string `json:"corsOrigins"` // CORSOrigins - источники, которым разрешены запросы из браузера ("*" - любые).
+	CORSMethods string   `json:"corsMethods"` // CORSMethods - методы, разрешенные для запросов с других источников.
+
+	AdminToken string   `json:"adminToken"` // AdminToken - токен для доступа к служебным обработчикам.
+	AdminCIDRs []string `json:"adminCIDRs"` // AdminCIDRs - сети, из которых доступны служебные обработчики (пустой - без ограничения).
+	TrustProxy bool     `json:"trustProxy"` // TrustProxy - брать адрес клиента из X-Forwarded-For и X-Real-IP.
+
+	RequestTimeout time.Duration `json:"requestTimeout"` // RequestTimeout - время, после которого сканирование по запросу прерывается (0 - без ограничения).
+
+	RateLimit float64 `json:"rateLimit"` // RateLimit - допустимое число запросов к списку файлов в секунду (0 - без ограничения).
+	RateBurst int     `json:"rateBurst"` // RateBurst - сколько запросов можно выполнить подряд сверх RateLimit.
+
+	ResponseCacheTTL        time.Duration `json:"responseCacheTTL"`        // ResponseCacheTTL - время хранения готовых ответов (0 - кэш отключен).
+	ResponseCacheMaxBytes   int           `json:"responseCacheMaxBytes"`   // ResponseCacheMaxBytes - максимальный размер сохраняемого ответа.
+	ResponseCacheTotalBytes int           `json:"responseCacheTotalBytes"` // ResponseCacheTotalBytes - максимальный общий размер сохраненных ответов (0 - без ограничения).
+
+	LinkSecret string        `json:"linkSecret"` // LinkSecret - секрет для подписи ссылок на просмотр директорий.
+	LinkTTL    time.Duration `json:"linkTTL"`    // LinkTTL - срок действия ссылок на просмотр директорий.
+
+	SecurityHeaders SecurityHeaders `json:"securityHeaders"` // SecurityHeaders - заголовки безопасности, добавляемые к ответам.
+
+	PidFile          string `json:"pidFile"`          // PidFile - файл, в который записывается PID сервера.
+	PidFileOverwrite bool   `json:"pidFileOverwrite"` // PidFileOverwrite - перезаписывать существующий pid-файл вместо ошибки.
+
+	WatchSizes []WatchConfig `json:"watchSizes"` // WatchSizes - директории, за ростом размера которых ведется наблюдение.
+
+	SLOMaxMs   int     `json:"sloMaxMs"`   // SLOMaxMs - допустимое время ответа в миллисекундах (0 - контроль отключен).
+	SLOMaxRate float64 `json:"sloMaxRate"` // SLOMaxRate - доля медленных ответов за минуту, после которой включается режим деградации.
+
+	RenameEnabled bool `json:"renameEnabled"` // RenameEnabled - разрешить массовое переименование файлов.
+
+	JournalMaxSize int64 `json:"journalMaxSize"` // JournalMaxSize - размер журнала изменений, после которого начинается новый файл (0 - без ограничения).
+	JournalKeep    int   `json:"journalKeep"`    // JournalKeep - количество сохраняемых старых файлов журнала изменений.
+
+	HealthVerbose bool `json:"healthVerbose"` // HealthVerbose - выводить в /health/live показатели работы сервера.
+
+	NoGzip bool `json:"noGzip"` // NoGzip - не сжимать ответы, например если сжатием занимается прокси.
+
+	BindNetwork string `json:"bindNetwork"` // BindNetwork - сеть для приема соединений: tcp, tcp4 или tcp6.
+	Addr        string `json:"addr"`        // Addr - адрес для приема соединений (пустой - из окружения или defaultAddr).
+
+	ShutdownTimeout time.Duration `json:"shutdownTimeout"` // ShutdownTimeout - время на завершение обработки запросов при остановке.
+
+	TLSCert string `json:"tlsCert"` // TLSCert - файл сертификата для HTTPS.
+	TLSKey  string `json:"tlsKey"`  // TLSKey - файл закрытого ключа для HTTPS.
+	TLSAuto bool   `json:"tlsAuto"` // TLSAuto - принимать HTTPS с самоподписанным сертификатом, созданным при запуске.
+
+	HTTPRedirectPort int `json:"httpRedirectPort"` // HTTPRedirectPort - порт HTTP, с которого запросы перенаправляются на HTTPS (0 - не слушать).
+
+	Banner string `json:"banner"` // Banner - объявление над страницей (поддерживает **жирный**, *курсив* и [ссылки](url)).
+
+	Dev bool `json:"dev"` // Dev - режим разработки: шаблоны и статические файлы читаются с диска.
+
+	ConfigFile string `json:"configFile"` // ConfigFile - файл, из которого прочитаны настройки.
+
+	MetricsAddr string `json:"metricsAddr"` // MetricsAddr - адрес отдельного сервера /metrics (пустой - /metrics на основном адресе).
+
+	LogFormat string `json:"logFormat"` // LogFormat - формат журнала: text или json.
+	AccessLog string `json:"accessLog"` // AccessLog - файл журнала доступа (пустой - stdout).
+	Logger    Logger `json:"-"`         // Logger - журнал сервера, создается по LogFormat после чтения настроек.
+}
+
+// defaultMaxZipBytes - ограничение --max-zip-bytes по умолчанию.
+const defaultMaxZipBytes = 1_000_000_000
+
+// config - настройки, с которыми запущен сервер.
+var config Config
+
+// networkMounts - сетевые точки монтирования, пропускаемые при --skip-network-mounts.
+var networkMounts filesystem.MountSet
+
+// redacted - значение, выводимое вместо заданных секретов.
+const redacted = "<redacted>"
+
+// MarshalJSON - метод для вывода настроек в JSON с замененными секретами.
+func (cfg Config) MarshalJSON() ([]byte, error) {
+	// plainConfig - тот же набор полей без метода MarshalJSON, чтобы избежать рекурсии.
+	type plainConfig Config
+	safe := plainConfig(cfg)
+	for _, secret := range []*string{&safe.AdminToken, &safe.LinkSecret, &safe.AuthPassword, &safe.APIKey} {
+		if *secret != "" {
+			*secret = redacted
+		}
+	}
+	return json.Marshal(safe)
+}
+
+// handleConfig - функция-обработчик для вывода текущих настроек сервера без секретов.
+func handleConfig(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, config)
+}
+
+// loadConfig - функция для чтения флагов командной строки. Если задан --config, настройки
+// читаются из файла, а явно указанные флаги командной строки имеют приоритет над ним.
+func loadConfig() Config {
+	var cfg Config
+	defineFlags(flag.CommandLine, &cfg)
+	flag.Parse()
+
+	if cfg.ConfigFile != "" {
+		fileCfg, err := readConfigFile(cfg.ConfigFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cfg = fileCfg
+	}
+
+	cfg.Logger = newLogger(cfg.LogFormat, os.Stderr)
+	if cfg.LogFormat == logFormatJSON {
+		// Сообщения, которые пишут через пакет log сторонние библиотеки и net/http, тоже выводим в JSON.
+		log.SetFlags(0)
+		log.SetOutput(loggerWriter{logger: cfg.Logger})
+	}
+	return cfg
+}
+
+// readConfigFile - функция для чтения настроек из файла path с применением поверх них
+// флагов командной строки. Используется при запуске и при перечитывании настроек по SIGHUP.
+func readConfigFile(path string) (Config, error) {
+	values, err := parseConfig(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	// Заново объявляем флаги для чистых настроек: сначала значения из файла, затем командная строка поверх них.
+	var fileCfg Config
+	fs := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	defineFlags(fs, &fileCfg)
+	if err := applyConfigValues(fs, values); err != nil {
+		return Config{}, fmt.Errorf("ошибка в файле настроек %s: %v", path, err)
+	}
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		return Config{}, err
+	}
+
+	return fileCfg, nil
+}
+
+// defineFlags - функция для объявления флагов настроек в наборе fs со значениями в cfg.
+// Используется и для командной строки, и для файлов настроек, ключи которых совпадают с именами флагов.
+func defineFlags(fs *flag.FlagSet, cfg *Config) {
+	cfg.BindNetwork = "tcp"
+	fs.StringVar(&cfg.ConfigFile, "config", "", "файл настроек (.json, .yaml или .toml), ключи совпадают с именами флагов")
+	fs.IntVar(&cfg.ListDepth, "list-depth", 1, "количество выводимых уровней вложенности (0 - без ограничения)")
+	fs.IntVar(&cfg.SizeDepth, "size-depth", 0, "глубина обхода при вычислении размера директорий (0 - без ограничения)")
+	fs.StringVar(&cfg.Lang, "lang", "ru", "язык интерфейса: ru, en или de")
+	cfg.SortLocale = filesystem.SortLocaleOS
+	fs.Func("sort-locale", "сравнение имен при сортировке: os - без учета регистра, unicode - по правилам Unicode с учетом букв с диакритикой (по умолчанию os)", func(value string) error {
+		switch value {
+		case filesystem.SortLocaleOS, filesystem.SortLocaleUnicode:
+			cfg.SortLocale = value
+			return nil
+		}
+		return fmt.Errorf("неподдерживаемое правило сортировки %q: используйте os или unicode", value)
+	})
+	fs.BoolVar(&cfg.FollowSymlinks, "follow-symlinks", false, "учитывать в размере содержимое директорий, на которые указывают символические ссылки")
+	fs.IntVar(&cfg.MaxGoroutines, "max-goroutines", 10000, "число горутин, после которого элементы директории обрабатываются последовательно (0 - без ограничения)")
+	fs.IntVar(&cfg.ScanWorkers, "scan-workers", filesystem.DefaultWorkers, "число горутин, обрабатывающих элементы директории при одном сканировании")
+	fs.Func("max-scan-size", "размер (например, 10GB), после которого подсчет размера директории останавливается (пустой - без ограничения)", func(value string) error {
+		size, err := parseByteSize(value)
+		cfg.MaxScanSize = size
+		return err
+	})
+	cfg.MaxZipBytes = defaultMaxZipBytes
+	fs.Func("max-zip-bytes", "наибольший общий размер файлов (например, 2GB), при котором директорию можно скачать архивом ZIP (0 - без ограничения, по умолчанию 1GB)", func(value string) error {
+		size, err := parseByteSize(value)
+		cfg.MaxZipBytes = size
+		return err
+	})
+	fs.BoolVar(&cfg.AllowUnicodeFilenames, "allow-unicode-filenames", false, "оставлять символы не из ASCII в имени архива, заданном параметром filename в /download-zip")
+	cfg.PreviewBytes = defaultPreviewBytes
+	fs.Func("preview-bytes", "сколько байт текстового файла выводится при предпросмотре, например 128KB (по умолчанию 64KB)", func(value string) error {
+		size, err := parseByteSize(value)
+		if err == nil && size <= 0 {
+			return fmt.Errorf("размер предпросмотра должен быть больше нуля")
+		}
+		cfg.PreviewBytes = size
+		return err
+	})
+	fs.Func("inline-types", "типы содержимого через запятую, которые /download отдает для просмотра в браузере, а не для сохранения, например image/*,application/pdf (пустой - всегда сохранение)", func(value string) error {
+		cfg.InlineTypes = nil
+		for _, mimeType := range strings.Split(value, ",") {
+			if mimeType = strings.ToLower(strings.TrimSpace(mimeType)); mimeType == "" {
+				continue
+			}
+			if !strings.Contains(mimeType, "/") {
+				return fmt.Errorf("неправильный тип содержимого %q: используйте вид image/png или image/*", mimeType)
+			}
+			cfg.InlineTypes = append(cfg.InlineTypes, mimeType)
+		}
+		return nil
+	})
+	fs.IntVar(&cfg.SearchMaxResults, "search-max-results", defaultSearchMaxResults, "наибольшее количество результатов поиска по именам в /api/search")
+	fs.IntVar(&cfg.DupWorkers, "dup-workers", runtime.NumCPU(), "число горутин, считающих SHA-256 при поиске одинаковых файлов в /api/duplicates")
+	cfg.DupMaxFileSize = defaultDupMaxFileSize
+	fs.Func("dup-max-file-size", "файлы больше этого размера (например, 4GB) не проверяются на совпадение в /api/duplicates (0 - без ограничения, по умолчанию 1GB)", func(value string) error {
+		size, err := parseByteSize(value)
+		cfg.DupMaxFileSize = size
+		return err
+	})
+	fs.BoolVar(&cfg.FastSize, "fast-size", false, "оценивать размер директорий через du -s --bytes (выводится как ~1.2 GB); без du размер считается обходом")
+	fs.BoolVar(&cfg.SkipNetworkMounts, "skip-network-mounts", false, "не сканировать содержимое сетевых точек монтирования (NFS, CIFS, SSHFS и т.п.)")
+	fs.BoolVar(&cfg.PartialOnError, "partial-on-error", false, "при ошибках чтения выводить прочитанные элементы с предупреждением вместо страницы ошибки")
+	fs.BoolVar(&cfg.GitBlame, "git-blame", false, "выводить автора и время последнего коммита для файлов в git-репозитории")
+	fs.Uint64Var(&cfg.MaxCacheMemory, "max-cache-memory", 100*1000*1000, "объем кучи в байтах, после которого кэш сканирования сокращается")
+	fs.DurationVar(&cfg.LeakMonitorInterval, "leak-monitor-interval", 0, "период проверки роста кучи для поиска утечек памяти (0 - проверка отключена)")
+	fs.Float64Var(&cfg.LeakMonitorThreshold, "leak-monitor-threshold", 10*1000*1000, "рост кучи в байтах в минуту, после которого выводится предупреждение")
+	fs.Func("forbidden", "абсолютные пути через запятую, просмотр которых запрещен вместе с содержимым, например /etc,/proc; флаг можно повторять", func(value string) error {
+		paths, err := parsePathList(value)
+		cfg.Forbidden = append(cfg.Forbidden, paths...)
+		return err
+	})
+	fs.Func("root-jail", "абсолютный путь к директории, за пределы которой просмотр запрещен", func(value string) error {
+		paths, err := parsePathList(value)
+		if err != nil {
+			return err
+		}
+		if len(paths) != 1 {
+			return fmt.Errorf("--root-jail принимает один путь")
+		}
+		cfg.RootJail = paths[0]
+		return nil
+	})
+	fs.StringVar(&cfg.AuthUser, "auth-user", "", "логин для входа на сервер по HTTP Basic, задается вместе с --auth-password")
+	fs.StringVar(&cfg.AuthPassword, "auth-password", "", "пароль для входа на сервер по HTTP Basic, задается вместе с --auth-user")
+	fs.StringVar(&cfg.APIKey, "api-key", "", "ключ, который запросы к /api/ передают в заголовке X-Api-Key (пустой - без ключа)")
+	fs.Func("cors-origins", "источники через запятую, которым разрешены запросы из браузера, например https://app.example.com; * - любые (пустой - CORS отключен)", func(value string) error {
+		cfg.CORSOrigins = nil
+		for _, origin := range strings.Split(value, ",") {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				cfg.CORSOrigins = append(cfg.CORSOrigins, origin)
+			}
+		}
+		return nil
+	})
+	fs.StringVar(&cfg.CORSMethods, "cors-methods", "GET, POST, DELETE, OPTIONS", "методы через запятую, разрешенные для запросов с других источников")
+	fs.StringVar(&cfg.AdminToken, "admin-token", "", "токен для доступа к служебным обработчикам (пустой - обработчики отключены)")
+	fs.Func("admin-cidr", "сети через запятую, из которых доступны служебные обработчики, например 127.0.0.1/8,10.0.0.0/8 (пустой - без ограничения)", func(value string) error {
+		cfg.AdminCIDRs = nil
+		for _, cidr := range strings.Split(value, ",") {
+			if cidr = strings.TrimSpace(cidr); cidr != "" {
+				cfg.AdminCIDRs = append(cfg.AdminCIDRs, cidr)
+			}
+		}
+		return nil
+	})
+	fs.BoolVar(&cfg.TrustProxy, "trust-proxy", false, "брать адрес клиента из заголовков X-Forwarded-For и X-Real-IP (только за доверенным прокси)")
+	fs.DurationVar(&cfg.RequestTimeout, "request-timeout", 30*time.Second, "время, после которого сканирование по запросу прерывается с ответом 503 (0 - без ограничения)")
+	fs.Float64Var(&cfg.RateLimit, "rate-limit", 0, "допустимое число запросов к списку файлов в секунду, остальные получают 429 (0 - без ограничения)")
+	fs.IntVar(&cfg.RateBurst, "rate-burst", 10, "сколько запросов к списку файлов можно выполнить подряд сверх --rate-limit")
+	fs.DurationVar(&cfg.ResponseCacheTTL, "response-cache-ttl", 0, "время хранения готовых ответов для одинаковых запросов (0 - кэш отключен)")
+	fs.IntVar(&cfg.ResponseCacheMaxBytes, "response-cache-max-bytes", 10*1000*1000, "максимальный размер ответа, сохраняемого в кэш")
+	fs.IntVar(&cfg.ResponseCacheTotalBytes, "response-cache-total-bytes", 100*1000*1000, "максимальный общий размер ответов в кэше, после которого вытесняются давно не использованные (0 - без ограничения)")
+	fs.StringVar(&cfg.LinkSecret, "link-secret", "", "секрет для подписи ссылок на просмотр директорий (пустой - ссылки отключены)")
+	fs.DurationVar(&cfg.LinkTTL, "link-ttl", 24*time.Hour, "срок действия ссылок на просмотр директорий")
+	fs.StringVar(&cfg.SecurityHeaders.ContentTypeOptions, "header-content-type-options", "nosniff", "значение X-Content-Type-Options (пустое - не отправлять)")
+	fs.StringVar(&cfg.SecurityHeaders.FrameOptions, "header-frame-options", "DENY", "значение X-Frame-Options (пустое - не отправлять)")
+	fs.StringVar(&cfg.SecurityHeaders.XSSProtection, "header-xss-protection", "0", "значение X-XSS-Protection (пустое - не отправлять)")
+	fs.StringVar(&cfg.SecurityHeaders.ReferrerPolicy, "header-referrer-policy", "strict-origin-when-cross-origin", "значение Referrer-Policy (пустое - не отправлять)")
+	fs.StringVar(&cfg.SecurityHeaders.ContentSecurityPolicy, "header-csp", "default-src 'self'", "значение Content-Security-Policy (пустое - не отправлять)")
+	fs.StringVar(&cfg.SecurityHeaders.ContentSecurityPolicy, "csp", "default-src 'self'", "то же, что --header-csp")
+	fs.StringVar(&cfg.SecurityHeaders.StrictTransportSecurity, "header-hsts", "max-age=31536000; includeSubDomains", "значение Strict-Transport-Security, отправляется только по HTTPS (пустое - не отправлять)")
+	fs.StringVar(&cfg.DefaultRoot, "default-root", "", "директория, открываемая при заходе на главную страницу без параметров (пустая - выводится главная страница)")
+	fs.StringVar(&cfg.CacheFile, "cache-file", "", "JSON-файл с результатами сканирования (массив FileInfo); если задан, файловая система не читается")
+	fs.Func("watch-sizes", `наблюдение за ростом директории в JSON: {"path":"/data","maxGrowthPct":10,"interval":"1h"} или массив таких объектов; флаг можно повторять`, func(value string) error {
+		watches, err := parseWatchSizes(value)
+		cfg.WatchSizes = append(cfg.WatchSizes, watches...)
+		return err
+	})
+	fs.IntVar(&cfg.SLOMaxMs, "slo-max-ms", 0, "допустимое время ответа в миллисекундах (0 - контроль отключен)")
+	fs.Float64Var(&cfg.SLOMaxRate, "slo-max-rate", 0.5, "доля медленных ответов за минуту, после которой выдаются только результаты из кэша")
+	fs.BoolVar(&cfg.RenameEnabled, "rename-enabled", false, "разрешить массовое переименование файлов через /api/bulk-rename (требует --admin-token)")
+	cfg.JournalMaxSize = defaultJournalMaxSize
+	fs.Func("journal-max-size", "размер журнала изменений ~/.filesystem/journal.log (например, 10MB), после которого он переименовывается в journal.log.1 и начинается новый (0 - без ограничения, по умолчанию 100MB)", func(value string) error {
+		size, err := parseByteSize(value)
+		cfg.JournalMaxSize = size
+		return err
+	})
+	fs.IntVar(&cfg.JournalKeep, "journal-keep", defaultJournalKeep, "количество сохраняемых старых файлов журнала изменений (journal.log.1, journal.log.2, ...)")
+	fs.BoolVar(&cfg.HealthVerbose, "health-verbose", false, "выводить в /health/live время работы, число горутин и занятую память")
+	fs.Func("bind-network", "сеть для приема соединений: tcp (IPv4 и IPv6), tcp4 или tcp6 (по умолчанию tcp)", func(value string) error {
+		switch value {
+		case "tcp", "tcp4", "tcp6":
+			cfg.BindNetwork = value
+			return nil
+		}
+		return fmt.Errorf("неподдерживаемая сеть %q: используйте tcp, tcp4 или tcp6", value)
+	})
+	fs.StringVar(&cfg.Addr, "addr", "", "адрес для приема соединений, например :9015 или 127.0.0.1:8080; имеет приоритет над переменными окружения FILESYSTEM_ADDR и SERVER_PORT (по умолчанию "+defaultAddr+")")
+	fs.DurationVar(&cfg.ShutdownTimeout, "shutdown-timeout", 5*time.Second, "время на завершение обработки запросов при остановке сервера")
+	fs.StringVar(&cfg.TLSCert, "tls-cert", "", "файл сертификата (PEM) для приема соединений по HTTPS, задается вместе с --tls-key")
+	fs.StringVar(&cfg.TLSKey, "tls-key", "", "файл закрытого ключа (PEM) для приема соединений по HTTPS, задается вместе с --tls-cert")
+	fs.IntVar(&cfg.HTTPRedirectPort, "http-redirect-port", 0, "порт HTTP, запросы на который перенаправляются (301) на HTTPS; работает вместе с --tls-cert или --tls-auto (0 - отключено)")
+	fs.BoolVar(&cfg.TLSAuto, "tls-auto", false, "принимать соединения по HTTPS с самоподписанным сертификатом на localhost, созданным при запуске (для разработки)")
+	fs.BoolVar(&cfg.NoGzip, "no-gzip", false, "не сжимать ответы gzip (например, если сжатием занимается обратный прокси)")
+	fs.StringVar(&cfg.PidFile, "pidfile", "", "файл, в который записывается PID сервера (пустой - не записывать)")
+	fs.BoolVar(&cfg.PidFileOverwrite, "pidfile-overwrite", false, "перезаписывать существующий pid-файл с предупреждением вместо завершения с ошибкой")
+	cfg.LogFormat = logFormatText
+	fs.Func("log-format", "формат журнала: text (по умолчанию) или json - по одному объекту с полями time, level, msg и requestId на строку", func(value string) error {
+		if value != logFormatText && value != logFormatJSON {
+			return fmt.Errorf("неизвестный формат журнала %q, ожидается text или json", value)
+		}
+		cfg.LogFormat = value
+		return nil
+	})
+	fs.StringVar(&cfg.AccessLog, "access-log", "", "файл журнала доступа в Combined Log Format, записи дописываются в конец (пустой - stdout)")
+	fs.StringVar(&cfg.MetricsAddr, "metrics-addr", "", "адрес отдельного сервера метрик Prometheus, например 127.0.0.1:9100 (пустой - /metrics на основном адресе)")
+	fs.BoolVar(&cfg.Dev, "dev", false, "режим разработки: шаблоны и статические файлы читаются с диска, измененные шаблоны разбираются заново без перезапуска")
+	fs.StringVar(&cfg.Banner, "banner", "", "объявление над страницей, например о технических работах; поддерживает **жирный**, *курсив* и [ссылки](url). Перечитывается из файла настроек по SIGHUP")
+	fs.Func("prewarm-paths", "директории через запятую, сканируемые в фоне при запуске сервера", func(value string) error {
+		cfg.PrewarmPaths = parsePrewarmPaths(value)
+		return nil
+	})
+	fs.DurationVar(&cfg.PrewarmTTL, "prewarm-ttl", 5*time.Minute, "время, в течение которого результаты сканирования прогретых директорий отдаются без повторного сканирования (0 - всегда сканировать заново)")
+}
+
+// defaultAddr - адрес сервера, если он не задан ни флагом, ни переменными окружения.
+const defaultAddr = ":9015"
+
+// listenAddr - функция для выбора адреса сервера. Флаг --addr имеет приоритет над переменной окружения
+// FILESYSTEM_ADDR, она - над SERVER_PORT из .env, оставленной для совместимости.
+func (cfg Config) listenAddr() string {
+	if cfg.Addr != "" {
+		return cfg.Addr
+	}
+	for _, name := range []string{"FILESYSTEM_ADDR", "SERVER_PORT"} {
+		if addr := os.Getenv(name); addr != "" {
+			return addr
+		}
+	}
+	return defaultAddr
+}
+
+// scanOptions - функция для формирования параметров сканирования из настроек сервера.
+func (cfg Config) scanOptions() filesystem.Options {
+	return filesystem.Options{
+		ListDepth: cfg.ListDepth,
+		SizeDepth: cfg.SizeDepth,
+
+		FollowSymlinks: cfg.FollowSymlinks,
+		MaxGoroutines:  cfg.MaxGoroutines,
+		Workers:        cfg.ScanWorkers,
+		MaxScanSize:    cfg.MaxScanSize,
+		PartialOnError: cfg.PartialOnError,
+
+		SkipMounts: networkMounts,
+
+		FastSize: cfg.FastSize,
+
+		Logger: cfg.Logger,
+
+		SkipDir: isForbiddenPath,
+	}
+}
+
+// requestScanOptions - метод для получения параметров сканирования по запросу r: предупреждения
+// сканирования пишутся в журнал с идентификатором запроса.
+func (cfg Config) requestScanOptions(r *http.Request) filesystem.Options {
+	opts := cfg.scanOptions()
+	opts.Logger = requestLogger(r)
+	return opts
+}
+
+// byteSizeUnits - множители единиц размера, те же десятичные, что и в ConvertSize.
+var byteSizeUnits = []struct {
+	suffix     string
+	multiplier float64
+}{
+	{"TB", 1e12},
+	{"GB", 1e9},
+	{"MB", 1e6},
+	{"KB", 1e3},
+	{"B", 1},
+}
+
+// parseByteSize - функция для разбора размера вида "10GB", "512MB" или "1024".
+func parseByteSize(value string) (int64, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	multiplier := 1.0
+	for _, unit := range byteSizeUnits {
+		if strings.HasSuffix(value, unit.suffix) {
+			value = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix))
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("неправильно указан размер %q", value)
+	}
+	return int64(number * multiplier), nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// parseConfig - функция для чтения файла настроек. Формат определяется по расширению:
+// .json, .yaml/.yml или .toml. Ключи файла совпадают с именами флагов командной строки.
+func parseConfig(path string) (map[string]interface{}, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения файла настроек: %w", err)
+	}
+
+	values := make(map[string]interface{})
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		// Числа читаем без перевода во float64, чтобы большие значения не превращались в 1e+08.
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		err = decoder.Decode(&values)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &values)
+	case ".toml":
+		err = toml.Unmarshal(content, &values)
+	default:
+		return nil, fmt.Errorf("неподдерживаемый формат файла настроек %s: используйте .json, .yaml или .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка разбора файла настроек %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// applyConfigValues - функция для установки значений из файла настроек в набор флагов.
+// Списки записываются через запятую, как в командной строке.
+func applyConfigValues(fs *flag.FlagSet, values map[string]interface{}) error {
+	// Применяем в порядке ключей, чтобы ошибки выводились предсказуемо.
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if fs.Lookup(key) == nil {
+			return fmt.Errorf("неизвестный параметр %q", key)
+		}
+		if err := fs.Set(key, configValue(values[key])); err != nil {
+			return fmt.Errorf("параметр %q: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// configValue - функция для перевода значения из файла настроек в строку флага.
+// Объекты и списки объектов записываются в JSON.
+func configValue(value interface{}) string {
+	if isConfigObject(value) {
+		if encoded, err := json.Marshal(value); err == nil {
+			return string(encoded)
+		}
+	}
+	if list, ok := value.([]interface{}); ok {
+		items := make([]string, len(list))
+		for i, item := range list {
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprint(value)
+}
+
+// isConfigObject - функция для проверки, является ли значение объектом или списком объектов.
+func isConfigObject(value interface{}) bool {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		return true
+	case []interface{}:
+		return len(value) > 0 && isConfigObject(value[0])
+	case []map[string]interface{}:
+		return true
+	}
+	return false
+}
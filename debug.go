@@ -0,0 +1,47 @@
+package main
+
+import (
+	"net/http"
+	"runtime"
+)
+
+// MemSnapshot - структура для передачи показателей памяти.
+type MemSnapshot struct {
+	HeapAlloc uint64 `json:"heapAlloc"` // HeapAlloc - занятая память кучи в байтах.
+	NumGC     uint32 `json:"numGC"`     // NumGC - количество завершенных циклов сборки мусора.
+}
+
+// GCResponse - структура ответа обработчика принудительной сборки мусора.
+type GCResponse struct {
+	Before     MemSnapshot `json:"before"`     // Before - показатели до сборки мусора.
+	After      MemSnapshot `json:"after"`      // After - показатели после сборки мусора.
+	FreedBytes uint64      `json:"freedBytes"` // FreedBytes - объем освобожденной памяти.
+}
+
+// handleDebugGC - функция-обработчик для принудительного запуска сборки мусора.
+func handleDebugGC(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSONError(w, http.StatusMethodNotAllowed, "метод не поддерживается")
+		return
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+
+	// Между замерами куча может вырасти за счет аллокаций других горутин и самой сборки,
+	// поэтому учитываем выделенное за это время: freed = before + allocated - after.
+	allocated := after.TotalAlloc - before.TotalAlloc
+	var freed uint64
+	if before.HeapAlloc+allocated > after.HeapAlloc {
+		freed = before.HeapAlloc + allocated - after.HeapAlloc
+	}
+
+	writeJSON(w, http.StatusOK, GCResponse{
+		Before:     MemSnapshot{HeapAlloc: before.HeapAlloc, NumGC: before.NumGC},
+		After:      MemSnapshot{HeapAlloc: after.HeapAlloc, NumGC: after.NumGC},
+		FreedBytes: freed,
+	})
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DeepLink - структура с параметрами просмотра, закодированными в ссылке.
+type DeepLink struct {
+	Root string `json:"root"`         // Root - путь к директории.
+	Sort string `json:"sort"`         // Sort - тип сортировки.
+	By   string `json:"by,omitempty"` // By - ключ сортировки.
+
+	DirsFirst bool  `json:"dirsFirst,omitempty"` // DirsFirst - выводить директории перед файлами.
+	Expires   int64 `json:"exp"`                 // Expires - время окончания действия ссылки (Unix-время).
+}
+
+// DeepLinkResponse - структура ответа с созданной ссылкой.
+type DeepLinkResponse struct {
+	URL string `json:"url"` // URL - относительный адрес ссылки.
+}
+
+// errInvalidLink - ошибка для поддельных и поврежденных ссылок.
+var errInvalidLink = errors.New("недействительная ссылка")
+
+// errExpiredLink - ошибка для ссылок с истекшим сроком действия.
+var errExpiredLink = errors.New("срок действия ссылки истек")
+
+// signDeepLink - функция для создания токена: данные в base64 и их HMAC-SHA256 подпись через точку.
+func signDeepLink(link DeepLink, secret string) (string, error) {
+	payload, err := json.Marshal(link)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// verifyDeepLink - функция для проверки подписи и срока действия токена.
+func verifyDeepLink(token, secret string, now time.Time) (DeepLink, error) {
+	var link DeepLink
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return link, errInvalidLink
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return link, errInvalidLink
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return link, errInvalidLink
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return link, errInvalidLink
+	}
+
+	if err := json.Unmarshal(payload, &link); err != nil {
+		return link, errInvalidLink
+	}
+	if now.Unix() > link.Expires {
+		return link, errExpiredLink
+	}
+	return link, nil
+}
+
+// handleDeepLink - функция-обработчик для создания подписанной ссылки на просмотр директории.
+func handleDeepLink(w http.ResponseWriter, r *http.Request) {
+	if config.LinkSecret == "" {
+		writeJSONError(w, http.StatusForbidden, "секрет для ссылок не настроен")
+		return
+	}
+
+	dirPath, sortOpts, err := parseFlags(r, resolveLang(r))
+	if err != nil {
+		status := http.StatusBadRequest
+		var forbidden *forbiddenPathError
+		if errors.As(err, &forbidden) {
+			status = http.StatusForbidden
+		}
+		writeJSONError(w, status, err.Error())
+		return
+	}
+
+	token, err := signDeepLink(DeepLink{
+		Root: dirPath,
+		Sort: sortOpts.Order,
+		By:   sortOpts.Key,
+
+		DirsFirst: sortOpts.DirsFirst,
+		Expires:   time.Now().Add(config.LinkTTL).Unix(),
+	}, config.LinkSecret)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, DeepLinkResponse{URL: "/view/" + token})
+}
+
+// handleView - функция-обработчик для перехода по подписанной ссылке /view/{токен}.
+func handleView(w http.ResponseWriter, r *http.Request) {
+	if config.LinkSecret == "" {
+		http.Error(w, "секрет для ссылок не настроен", http.StatusForbidden)
+		return
+	}
+
+	link, err := verifyDeepLink(strings.TrimPrefix(r.URL.Path, "/view/"), config.LinkSecret, time.Now())
+	if err != nil {
+		status := http.StatusForbidden
+		if errors.Is(err, errExpiredLink) {
+			status = http.StatusGone
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	query := url.Values{}
+	query.Set("root", link.Root)
+	query.Set("sort", link.Sort)
+	if link.By != "" {
+		query.Set("by", link.By)
+	}
+	if link.DirsFirst {
+		query.Set("dirsFirst", "true")
+	}
+	http.Redirect(w, r, "/?"+query.Encode(), http.StatusFound)
+}
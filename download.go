@@ -0,0 +1,90 @@
+package main
+
+import (
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	filesystem "filesystem/file_system"
+)
+
+// handleDownload - функция-обработчик для скачивания файла. Путь проверяется по тем же правилам
+// --forbidden и --root-jail, что и при просмотре директорий. http.ServeContent сам обрабатывает
+// запросы диапазонов и условные запросы по времени изменения. Файлы с типом из --inline-types
+// отдаются с Content-Disposition: inline для просмотра в браузере, остальные - для сохранения.
+func handleDownload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeJSONError(w, http.StatusMethodNotAllowed, "метод не поддерживается")
+		return
+	}
+
+	filePath := filepath.Clean(r.URL.Query().Get("path"))
+	if r.URL.Query().Get("path") == "" {
+		writeJSONError(w, http.StatusBadRequest, "не указан path")
+		return
+	}
+	if err := checkPath(filePath, resolveLang(r)); err != nil {
+		writeJSONError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		writeJSONError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !info.Mode().IsRegular() {
+		writeJSONError(w, http.StatusBadRequest, "path указывает не на обычный файл")
+		return
+	}
+
+	disposition := "attachment"
+	if mimeType := filesystem.DetectMIMEType(filePath); mimeType != "" {
+		w.Header().Set("Content-Type", mimeType)
+		if inlineType(mimeType) {
+			disposition = "inline"
+		}
+	}
+	// FormatMediaType кодирует имена не в ASCII по RFC 2231, чтобы браузер сохранил файл под исходным именем.
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": info.Name()}))
+	http.ServeContent(w, r, info.Name(), info.ModTime(), file)
+}
+
+// inlineType - функция для проверки, входит ли тип содержимого mimeType в --inline-types.
+// Параметры типа (charset и т.п.) не учитываются, шаблон вида image/* совпадает со всей группой.
+func inlineType(mimeType string) bool {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return false
+	}
+	for _, pattern := range config.InlineTypes {
+		if group := strings.TrimSuffix(pattern, "*"); group != pattern && strings.HasPrefix(mediaType, group) {
+			return true
+		}
+		if pattern == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// setPreviewURLs - функция для заполнения PreviewURL у файлов списка, тип которых входит в --inline-types.
+func setPreviewURLs(fileList []filesystem.FileInfo) {
+	for i, fileInfo := range fileList {
+		if fileInfo.IsDir || fileInfo.IsSymlink || !inlineType(fileInfo.MIMEType) {
+			continue
+		}
+		fileList[i].PreviewURL = "/download?" + url.Values{"path": {fileInfo.Path}}.Encode()
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	filesystem "filesystem/file_system"
+)
+
+func TestHandleDownloadContentDisposition(t *testing.T) {
+	saved := config
+	t.Cleanup(func() { config = saved })
+	config.InlineTypes = []string{"image/*", "application/pdf"}
+
+	dir := t.TempDir()
+	tests := []struct {
+		name        string
+		content     string
+		disposition string
+	}{
+		{"photo.png", "\x89PNG\r\n\x1a\n", "inline"},
+		{"photo.JPG", "jpeg", "inline"},
+		{"doc.pdf", "%PDF-1.4", "inline"},
+		{"notes.txt", "text", "attachment"},
+		{"page.html", "<html></html>", "attachment"},
+		{"archive.zip", "PK", "attachment"},
+		{"no-extension", "plain text", "attachment"},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name)
+		if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		rec := httptest.NewRecorder()
+		handleDownload(rec, httptest.NewRequest(http.MethodGet, "/download?"+url.Values{"path": {path}}.Encode(), nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, body %s", tt.name, rec.Code, rec.Body)
+		}
+		got := rec.Header().Get("Content-Disposition")
+		if want := tt.disposition + `; filename=` + tt.name; got != want {
+			t.Errorf("%s: Content-Disposition = %q, want %q", tt.name, got, want)
+		}
+	}
+}
+
+func TestInlineType(t *testing.T) {
+	saved := config
+	t.Cleanup(func() { config = saved })
+	config.InlineTypes = []string{"image/*", "application/pdf"}
+
+	tests := []struct {
+		mimeType string
+		want     bool
+	}{
+		{"image/png", true},
+		{"image/svg+xml", true},
+		{"application/pdf", true},
+		{"text/plain; charset=utf-8", false},
+		{"application/pdf-like", false},
+		{"imagefoo/png", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := inlineType(tt.mimeType); got != tt.want {
+			t.Errorf("inlineType(%q) = %v, want %v", tt.mimeType, got, tt.want)
+		}
+	}
+}
+
+func TestSetPreviewURLs(t *testing.T) {
+	saved := config
+	t.Cleanup(func() { config = saved })
+	config.InlineTypes = []string{"image/*"}
+
+	fileList := []filesystem.FileInfo{
+		{Name: "a b.png", Path: "/srv/a b.png", MIMEType: "image/png"},
+		{Name: "a.txt", Path: "/srv/a.txt", MIMEType: "text/plain; charset=utf-8"},
+		{Name: "pics", Path: "/srv/pics", IsDir: true, MIMEType: filesystem.MIMEDirectory},
+		{Name: "link.png", Path: "/srv/link.png", IsSymlink: true, MIMEType: "image/png"},
+	}
+	setPreviewURLs(fileList)
+
+	if got, want := fileList[0].PreviewURL, "/download?path=%2Fsrv%2Fa+b.png"; got != want {
+		t.Errorf("PreviewURL = %q, want %q", got, want)
+	}
+	for _, fileInfo := range fileList[1:] {
+		if fileInfo.PreviewURL != "" {
+			t.Errorf("%s: unexpected PreviewURL %q", fileInfo.Name, fileInfo.PreviewURL)
+		}
+	}
+}
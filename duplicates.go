@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+)
+
+// defaultDupMaxFileSize - ограничение --dup-max-file-size по умолчанию.
+const defaultDupMaxFileSize = 1_000_000_000
+
+// DuplicateGroup - группа файлов с одинаковым содержимым.
+type DuplicateGroup struct {
+	Size   int64    `json:"size"`   // Size - размер каждого файла группы в байтах.
+	SHA256 string   `json:"sha256"` // SHA256 - общая контрольная сумма файлов.
+	Paths  []string `json:"paths"`  // Paths - полные пути к файлам, по алфавиту.
+}
+
+// handleDuplicates - функция-обработчик для поиска одинаковых файлов в дереве root. Файлы сначала
+// группируются по размеру, и только в группах из нескольких файлов считается SHA-256 в
+// --dup-workers горутин. Пустые файлы и файлы больше --dup-max-file-size не проверяются.
+// Группы выводятся по убыванию места, которое занимают лишние копии.
+func handleDuplicates(w http.ResponseWriter, r *http.Request) {
+	root, ok := treeRoot(w, r)
+	if !ok {
+		return
+	}
+
+	bySize := make(map[int64][]string)
+	err := walkTree(r, root, func(path string, d fs.DirEntry) error {
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.Size() == 0 || (config.DupMaxFileSize > 0 && info.Size() > config.DupMaxFileSize) {
+			return nil
+		}
+		bySize[info.Size()] = append(bySize[info.Size()], path)
+		return nil
+	})
+	if err != nil {
+		// Клиент отключился или истекло время запроса.
+		return
+	}
+
+	groups := hashDuplicates(r, bySize)
+	if r.Context().Err() != nil {
+		return
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		wasteI := groups[i].Size * int64(len(groups[i].Paths)-1)
+		wasteJ := groups[j].Size * int64(len(groups[j].Paths)-1)
+		if wasteI != wasteJ {
+			return wasteI > wasteJ
+		}
+		return groups[i].Paths[0] < groups[j].Paths[0]
+	})
+	writeJSON(w, http.StatusOK, groups)
+}
+
+// hashJob - файл, контрольную сумму которого нужно посчитать.
+type hashJob struct {
+	path string
+	size int64
+}
+
+// hashDuplicates - функция для подсчета SHA-256 файлов из групп одинакового размера и сборки групп
+// с одинаковой суммой. Файлы, которые не удалось прочитать, пропускаются.
+func hashDuplicates(r *http.Request, bySize map[int64][]string) []DuplicateGroup {
+	jobs := make(chan hashJob)
+	var mu sync.Mutex
+	byHash := make(map[string]*DuplicateGroup)
+
+	workers := config.DupWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				sum, err := fileSHA256(r, job.path)
+				if err != nil {
+					continue
+				}
+				mu.Lock()
+				group, ok := byHash[sum]
+				if !ok {
+					group = &DuplicateGroup{Size: job.size, SHA256: sum}
+					byHash[sum] = group
+				}
+				group.Paths = append(group.Paths, job.path)
+				mu.Unlock()
+			}
+		}()
+	}
+
+send:
+	for size, paths := range bySize {
+		if len(paths) < 2 {
+			continue
+		}
+		for _, path := range paths {
+			select {
+			case jobs <- hashJob{path: path, size: size}:
+			case <-r.Context().Done():
+				break send
+			}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	groups := []DuplicateGroup{}
+	for _, group := range byHash {
+		if len(group.Paths) > 1 {
+			sort.Strings(group.Paths)
+			groups = append(groups, *group)
+		}
+	}
+	return groups
+}
+
+// fileSHA256 - функция для подсчета SHA-256 файла. Чтение прерывается при отмене запроса.
+func fileSHA256(r *http.Request, path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, contextReader{ctx: r.Context(), r: file}); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// contextReader - обертка над io.Reader, прекращающая чтение после отмены контекста.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// Read - метод для чтения с проверкой контекста перед каждым вызовом.
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/http"
+
+	filesystem "filesystem/file_system"
+)
+
+// ExecutablesResponse - структура ответа со списком исполняемых файлов.
+type ExecutablesResponse struct {
+	Root   string                                 `json:"root"`   // Root - просканированная директория.
+	Count  int                                    `json:"count"`  // Count - общее количество найденных файлов.
+	Owners map[string][]filesystem.ExecutableFile `json:"owners"` // Owners - исполняемые файлы, сгруппированные по владельцам.
+}
+
+// handleExecutables - функция-обработчик для вывода исполняемых файлов в дереве директории.
+func handleExecutables(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeJSONError(w, http.StatusMethodNotAllowed, "метод не поддерживается")
+		return
+	}
+
+	dirPath := r.URL.Query().Get("root")
+	if dirPath == "" {
+		writeJSONError(w, http.StatusBadRequest, "не указана директория(root)")
+		return
+	}
+	if err := checkPath(dirPath, resolveLang(r)); err != nil {
+		writeJSONError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	owners, err := filesystem.FindExecutables(dirPath, requestLogger(r))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	count := 0
+	for _, files := range owners {
+		count += len(files)
+	}
+	writeJSON(w, http.StatusOK, ExecutablesResponse{Root: dirPath, Count: count, Owners: owners})
+}
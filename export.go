@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	filesystem "filesystem/file_system"
+)
+
+// csvHeader - заголовок таблицы при выгрузке списка файлов в CSV.
+var csvHeader = []string{"Name", "Size", "Unit", "IsDir", "Path", "ModTime", "MIMEType"}
+
+// handleExportCSV - функция-обработчик для выгрузки списка файлов директории в CSV. Принимает те же
+// параметры root, sort, by, dirsFirst, depth и binary, что и главная страница, и сканирует директорию
+// так же. Директории, которые не удалось прочитать, пропускаются, как при частичном сканировании.
+func handleExportCSV(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeJSONError(w, http.StatusMethodNotAllowed, "метод не поддерживается")
+		return
+	}
+
+	lang := resolveLang(r)
+	dirPath, sortOpts, err := parseFlags(r, lang)
+	if err != nil {
+		status := http.StatusBadRequest
+		var forbidden *forbiddenPathError
+		if errors.As(err, &forbidden) {
+			status = http.StatusForbidden
+		}
+		writeJSONError(w, status, err.Error())
+		return
+	}
+	scanOpts := config.requestScanOptions(r)
+	if scanOpts.ListDepth, err = parseDepth(r, lang); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	binary, err := parseBinary(r, lang)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var fileList []filesystem.FileInfo
+	if config.CacheFile != "" {
+		fileList, _, err = listDirFromCache(dirPath)
+	} else {
+		fileList, _, err = filesystem.ListDirWithSkipped(r.Context(), dirPath, scanOpts)
+	}
+	var partial *filesystem.PartialError
+	if errors.As(err, &partial) {
+		err = nil
+	}
+	if r.Context().Err() != nil {
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filesystem.SortFileList(fileList, sortOpts)
+	convertFileListSizes(fileList, binary, lang)
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": exportName(dirPath) + ".csv"}))
+
+	writer := csv.NewWriter(w)
+	_ = writer.Write(csvHeader)
+	for _, file := range fileList {
+		modTime := ""
+		if !file.ModTime.IsZero() {
+			modTime = file.ModTime.Format(time.RFC3339)
+		}
+		_ = writer.Write([]string{
+			file.Name,
+			strconv.FormatFloat(file.Size, 'f', -1, 64),
+			file.Unit,
+			strconv.FormatBool(file.IsDir),
+			file.Path,
+			modTime,
+			file.MIMEType,
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		requestLogger(r).Error("Ошибка при выгрузке CSV:", err)
+	}
+}
+
+// exportName - функция для получения имени выгружаемого файла по директории ("root" для корня).
+func exportName(dirPath string) string {
+	name := filepath.Base(dirPath)
+	if name == string(filepath.Separator) || name == "." {
+		return "root"
+	}
+	return name
+}
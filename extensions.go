@@ -0,0 +1,59 @@
+package main
+
+import (
+	"io/fs"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ExtStat - сводка по файлам с одним расширением.
+type ExtStat struct {
+	Extension  string `json:"extension"`  // Extension - расширение в нижнем регистре с точкой (пустое - файлы без расширения).
+	Count      int    `json:"count"`      // Count - количество файлов.
+	TotalBytes int64  `json:"totalBytes"` // TotalBytes - суммарный размер файлов в байтах.
+}
+
+// handleByExtension - функция-обработчик для сводки занятого места по расширениям файлов во всем
+// дереве root. Расширения сравниваются без учета регистра, сводка выводится по убыванию размера.
+func handleByExtension(w http.ResponseWriter, r *http.Request) {
+	root, ok := treeRoot(w, r)
+	if !ok {
+		return
+	}
+
+	byExt := make(map[string]ExtStat)
+	err := walkTree(r, root, func(path string, d fs.DirEntry) error {
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(d.Name()))
+		stat := byExt[ext]
+		stat.Count++
+		stat.TotalBytes += info.Size()
+		byExt[ext] = stat
+		return nil
+	})
+	if err != nil {
+		// Клиент отключился или истекло время запроса.
+		return
+	}
+
+	summary := make([]ExtStat, 0, len(byExt))
+	for ext, stat := range byExt {
+		stat.Extension = ext
+		summary = append(summary, stat)
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].TotalBytes != summary[j].TotalBytes {
+			return summary[i].TotalBytes > summary[j].TotalBytes
+		}
+		return summary[i].Extension < summary[j].Extension
+	})
+	writeJSON(w, http.StatusOK, summary)
+}
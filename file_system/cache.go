@@ -0,0 +1,108 @@
+package filesystem
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// ScanCache - LRU-кэш результатов сканирования, ключом служит путь к директории.
+type ScanCache struct {
+	mu       sync.Mutex
+	capacity int                      // capacity - максимальное количество хранимых путей.
+	order    *list.List               // order - порядок использования, в начале самые свежие записи.
+	items    map[string]*list.Element // items - быстрый доступ к элементам списка по пути.
+}
+
+// cacheEntry - запись кэша.
+type cacheEntry struct {
+	path     string
+	fileList []FileInfo
+	stored   time.Time // stored - время сохранения результата.
+}
+
+// NewScanCache - функция для создания кэша заданной вместимости.
+func NewScanCache(capacity int) *ScanCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &ScanCache{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Get - метод для получения сохраненного результата сканирования по пути.
+func (c *ScanCache) Get(path string) ([]FileInfo, bool) {
+	return c.GetFresh(path, 0)
+}
+
+// GetFresh - метод для получения результата сканирования, сохраненного не раньше maxAge назад
+// (0 - без ограничения возраста).
+func (c *ScanCache) GetFresh(path string, maxAge time.Duration) ([]FileInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[path]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if maxAge > 0 && time.Since(entry.stored) > maxAge {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return copyFileList(entry.fileList), true
+}
+
+// Put - метод для сохранения результата сканирования. При переполнении вытесняется самая старая запись.
+func (c *ScanCache) Put(path string, fileList []FileInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[path]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.fileList = copyFileList(fileList)
+		entry.stored = time.Now()
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[path] = c.order.PushFront(&cacheEntry{path: path, fileList: copyFileList(fileList), stored: time.Now()})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheEntry).path)
+	}
+}
+
+// copyFileList - функция для копирования списка, чтобы кэш не зависел от изменений вызывающего кода.
+func copyFileList(fileList []FileInfo) []FileInfo {
+	result := make([]FileInfo, len(fileList))
+	copy(result, fileList)
+	return result
+}
+
+// Len - метод для получения количества записей в кэше.
+func (c *ScanCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// EvictHalf - метод для вытеснения половины записей, начиная с давно не использованных.
+// Возвращает количество вытесненных записей.
+func (c *ScanCache) EvictHalf() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	count := (c.order.Len() + 1) / 2
+	for i := 0; i < count; i++ {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheEntry).path)
+	}
+	return count
+}
@@ -0,0 +1,118 @@
+package filesystem
+
+import (
+	"compress/flate"
+	"io"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// compressionSampleBytes - сколько байт читается из каждого выбранного файла.
+const compressionSampleBytes = 64 * 1024
+
+// CompressionEstimate - структура с оценкой сжатия содержимого директории.
+type CompressionEstimate struct {
+	SampledFiles             int     `json:"sampledFiles"`             // SampledFiles - количество файлов в выборке.
+	EstimatedRatio           float64 `json:"estimatedRatio"`           // EstimatedRatio - отношение сжатого размера выборки к исходному.
+	EstimatedCompressedBytes int64   `json:"estimatedCompressedBytes"` // EstimatedCompressedBytes - ожидаемый размер всей директории после сжатия.
+	TotalBytes               int64   `json:"totalBytes"`               // TotalBytes - размер всех файлов директории.
+	Approximate              bool    `json:"approximate"`              // Approximate - оценка приблизительная, так как строится по выборке.
+}
+
+// EstimateCompression - функция для оценки сжатия файлов в дереве root. Случайно выбирается
+// samplePct процентов файлов (не меньше одного), из каждого сжимается до 64 КБ, а полученный
+// коэффициент переносится на общий размер директории. Ошибки чтения пишутся в logger (nil - в stdout).
+func EstimateCompression(root string, samplePct float64, logger Logger) (CompressionEstimate, error) {
+	logger = loggerOrDefault(logger)
+	estimate := CompressionEstimate{Approximate: true}
+
+	var files []string
+	err := filepath.WalkDir(root, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if filePath == root {
+				return err
+			}
+			logger.Error("ошибка чтения при оценке сжатия:", err)
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		estimate.TotalBytes += info.Size()
+		files = append(files, filePath)
+		return nil
+	})
+	if err != nil {
+		return estimate, err
+	}
+	if len(files) == 0 {
+		return estimate, nil
+	}
+
+	// Выбираем случайные файлы для выборки.
+	sampleSize := int(float64(len(files)) * samplePct / 100)
+	if sampleSize < 1 {
+		sampleSize = 1
+	}
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	rng.Shuffle(len(files), func(i, j int) { files[i], files[j] = files[j], files[i] })
+
+	var original, compressed int64
+	for _, filePath := range files[:sampleSize] {
+		read, written, err := compressSample(filePath)
+		if err != nil {
+			logger.Error("ошибка чтения файла при оценке сжатия:", err)
+			continue
+		}
+		original += read
+		compressed += written
+		estimate.SampledFiles++
+	}
+
+	if original > 0 {
+		estimate.EstimatedRatio = float64(compressed) / float64(original)
+		estimate.EstimatedCompressedBytes = int64(float64(estimate.TotalBytes) * estimate.EstimatedRatio)
+	}
+	return estimate, nil
+}
+
+// compressSample - функция для сжатия начала файла. Возвращает прочитанный и сжатый размеры.
+func compressSample(filePath string) (int64, int64, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	counter := &countingWriter{}
+	writer, err := flate.NewWriter(counter, flate.DefaultCompression)
+	if err != nil {
+		return 0, 0, err
+	}
+	read, err := io.Copy(writer, io.LimitReader(file, compressionSampleBytes))
+	if err != nil {
+		return 0, 0, err
+	}
+	if err := writer.Close(); err != nil {
+		return 0, 0, err
+	}
+	return read, counter.n, nil
+}
+
+// countingWriter - writer, который только считает количество записанных байт.
+type countingWriter struct {
+	n int64
+}
+
+// Write - метод для учета записанных байт.
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,28 @@
+//go:build !windows
+
+package filesystem
+
+import (
+	"os"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// isExecutable - функция для проверки, установлен ли у файла хотя бы один бит исполнения.
+func isExecutable(path string, mode os.FileMode) bool {
+	return mode.IsRegular() && mode&0111 != 0
+}
+
+// fileOwner - функция для получения имени владельца файла. Если пользователь не найден, возвращается его uid.
+func fileOwner(info os.FileInfo) string {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return ""
+	}
+	uid := strconv.FormatUint(uint64(stat.Uid), 10)
+	if u, err := user.LookupId(uid); err == nil {
+		return u.Username
+	}
+	return uid
+}
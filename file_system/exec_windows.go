@@ -0,0 +1,28 @@
+//go:build windows
+
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// executableExts - расширения исполняемых файлов в Windows.
+var executableExts = map[string]bool{
+	".exe": true,
+	".bat": true,
+	".cmd": true,
+	".ps1": true,
+}
+
+// isExecutable - функция для проверки, является ли файл исполняемым. В Windows биты исполнения
+// не используются, поэтому проверяется расширение.
+func isExecutable(path string, mode os.FileMode) bool {
+	return mode.IsRegular() && executableExts[strings.ToLower(filepath.Ext(path))]
+}
+
+// fileOwner - функция для получения имени владельца файла. В Windows не поддерживается.
+func fileOwner(info os.FileInfo) string {
+	return ""
+}
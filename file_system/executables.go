@@ -0,0 +1,55 @@
+package filesystem
+
+import (
+	"io/fs"
+	"path/filepath"
+)
+
+// ExecutableFile - структура для хранения сведений об исполняемом файле.
+type ExecutableFile struct {
+	Path string `json:"path"` // Path - полный путь к файлу.
+	Mode string `json:"mode"` // Mode - права доступа в виде строки, например "-rwxr-xr-x".
+	Size int64  `json:"size"` // Size - размер файла в байтах.
+}
+
+// FindExecutables - функция для поиска исполняемых файлов в дереве root.
+// Результат сгруппирован по владельцам файлов. Недоступные директории пропускаются,
+// ошибки чтения пишутся в logger (nil - в stdout).
+func FindExecutables(root string, logger Logger) (map[string][]ExecutableFile, error) {
+	logger = loggerOrDefault(logger)
+	owners := make(map[string][]ExecutableFile)
+
+	err := filepath.WalkDir(root, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if filePath == root {
+				return err
+			}
+			logger.Error("ошибка чтения при поиске исполняемых файлов:", err)
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			logger.Error("ошибка получения информации о файле:", err)
+			return nil
+		}
+		if !isExecutable(filePath, info.Mode()) {
+			return nil
+		}
+
+		owner := fileOwner(info)
+		owners[owner] = append(owners[owner], ExecutableFile{
+			Path: filePath,
+			Mode: info.Mode().String(),
+			Size: info.Size(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return owners, nil
+}
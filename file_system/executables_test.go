@@ -0,0 +1,80 @@
+//go:build !windows
+
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// discardLogger - журнал для тестов, отбрасывающий все записи.
+type discardLogger struct{}
+
+func (discardLogger) Info(args ...interface{})  {}
+func (discardLogger) Error(args ...interface{}) {}
+
+func TestFindExecutables(t *testing.T) {
+	root := t.TempDir()
+	files := []struct {
+		name string
+		mode os.FileMode
+	}{
+		{"run.sh", 0755},
+		{"owner-only", 0700},
+		{"group-only", 0610},
+		{"sub/tool", 0751},
+		{"readme.txt", 0644},
+		{"sub/data.bin", 0600},
+	}
+	for _, f := range files {
+		path := filepath.Join(root, f.name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		// Права выставляем отдельно, чтобы на них не влияла umask.
+		if err := os.Chmod(path, f.mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	owners, err := FindExecutables(root, discardLogger{})
+	if err != nil {
+		t.Fatalf("FindExecutables: %v", err)
+	}
+
+	var got []string
+	for _, executables := range owners {
+		for _, executable := range executables {
+			rel, err := filepath.Rel(root, executable.Path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, rel)
+			if executable.Size != 1 {
+				t.Errorf("%s: Size = %d, want 1", rel, executable.Size)
+			}
+		}
+	}
+	sort.Strings(got)
+
+	want := []string{"group-only", "owner-only", "run.sh", filepath.Join("sub", "tool")}
+	if len(got) != len(want) {
+		t.Fatalf("FindExecutables = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("FindExecutables = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestFindExecutablesMissingRoot(t *testing.T) {
+	if _, err := FindExecutables(filepath.Join(t.TempDir(), "missing"), discardLogger{}); err == nil {
+		t.Fatal("FindExecutables for a missing root returned no error")
+	}
+}
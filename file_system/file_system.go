@@ -1,12 +1,20 @@
 package filesystem
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"math"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
 )
 
 // FileInfo - структура для хранения информации о файле/директории.
@@ -16,118 +24,567 @@ type FileInfo struct {
 	Unit  string  // Unit - поле для хранения системы счисления размера.
 	IsDir bool    // IsDir - является ли директорией.
 	Path  string  // Path - поле для перезаписи пути.
+
+	ModTime time.Time // ModTime - время последнего изменения.
+
+	MIMEType   string // MIMEType - тип содержимого; для директорий MIMEDirectory.
+	PreviewURL string // PreviewURL - адрес для просмотра файла в браузере (например, в iframe), если его тип это позволяет.
+
+	Mode        os.FileMode // Mode - тип и права доступа.
+	Permissions string      // Permissions - права доступа в виде строки, например "-rwxr-xr-x".
+
+	SizeEstimate bool // SizeEstimate - размер директории оценен через du и может отличаться от точного.
+	FileCount    int  // FileCount - количество файлов в директории с учетом вложенных (при оценке через du не считается).
+
+	IsExecutable bool // IsExecutable - файл исполняемый (бит исполнения, в Windows - расширение).
+
+	IsSymlink     bool   // IsSymlink - элемент является символической ссылкой.
+	SymlinkTarget string // SymlinkTarget - путь, на который указывает ссылка, в том виде, как он записан.
+	IsBroken      bool   // IsBroken - ссылка указывает на несуществующий объект.
+
+	NameSanitised bool // NameSanitised - в имени были недопустимые символы, замененные на "?".
+
+	LastCommitAuthor string    // LastCommitAuthor - автор последнего коммита, затронувшего файл.
+	LastCommitEmail  string    // LastCommitEmail - почта автора последнего коммита.
+	LastCommitTime   time.Time // LastCommitTime - время последнего коммита.
+}
+
+// Options - структура для хранения параметров сканирования.
+type Options struct {
+	ListDepth int // ListDepth - количество выводимых уровней вложенности (0 - без ограничения).
+	SizeDepth int // SizeDepth - глубина обхода при вычислении размера директорий (0 - без ограничения).
+
+	FollowSymlinks bool // FollowSymlinks - учитывать содержимое директорий, на которые указывают символические ссылки.
+	MaxGoroutines  int  // MaxGoroutines - число горутин в процессе, после которого элементы обрабатываются последовательно (0 - без ограничения).
+
+	MaxScanSize int64 // MaxScanSize - размер в байтах, после которого подсчет размера директории останавливается (0 - без ограничения).
+
+	SkipMounts MountSet // SkipMounts - точки монтирования, содержимое которых не сканируется.
+
+	PartialOnError bool // PartialOnError - при ошибках чтения возвращать прочитанные элементы вместе с *PartialError.
+
+	FastSize bool // FastSize - оценивать размер директорий через du вместо полного обхода.
+
+	Workers int // Workers - число горутин, обрабатывающих элементы директории (0 - DefaultWorkers).
+
+	Logger Logger // Logger - журнал для предупреждений и ошибок сканирования (nil - вывод в stdout).
+
+	SkipDir func(path string) bool // SkipDir - возвращает true для директорий, которые не выводятся и не обходятся (nil - выводятся все).
+}
+
+// DefaultWorkers - число горутин пула сканирования по умолчанию.
+const DefaultWorkers = 32
+
+// PartialError - ошибка сканирования, при которой часть элементов все же удалось прочитать.
+type PartialError struct {
+	Errors []error // Errors - ошибки чтения отдельных директорий.
+}
+
+// Error - метод для вывода всех ошибок сканирования одной строкой.
+func (e *PartialError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return "сканирование выполнено частично: " + strings.Join(messages, "; ")
+}
+
+// SkipStats - структура со сведениями об элементах, скрытых правилами исключения.
+type SkipStats struct {
+	Count int     // Count - количество скрытых элементов.
+	Bytes float64 // Bytes - суммарный размер скрытых файлов (директории не учитываются).
 }
 
 // ListDirByReadDir - функция для обхода директории и сбора информации.
-func ListDirByReadDir(path string) ([]FileInfo, error) {
+// При opts.PartialOnError ошибки чтения не прерывают обход: возвращаются прочитанные элементы и *PartialError.
+// При отмене ctx обход прерывается и возвращается ошибка контекста.
+func ListDirByReadDir(ctx context.Context, path string, opts Options) ([]FileInfo, error) {
+	fileList, _, err := ListDirWithSkipped(ctx, path, opts)
+	return fileList, err
+}
+
+// ListDirWithSkipped - функция для обхода директории, дополнительно возвращающая сведения о скрытых элементах.
+func ListDirWithSkipped(ctx context.Context, path string, opts Options) ([]FileInfo, SkipStats, error) {
 	var fileList []FileInfo
-	var wg sync.WaitGroup
+	var skipped SkipStats
 	var mu sync.Mutex
 
-	// Читаем содержимое текущей директории.
-	filesAndDirs, err := os.ReadDir(path)
-	if err != nil {
-		fmt.Println("ошибка чтения директории:", err)
-		return nil, err
+	err := scanDir(ctx, path, opts, &skipped, func(fileInfo FileInfo) {
+		mu.Lock()
+		fileList = append(fileList, fileInfo)
+		mu.Unlock()
+	})
+	var partial *PartialError
+	if err != nil && !errors.As(err, &partial) {
+		return nil, skipped, err
 	}
+	return fileList, skipped, err
+}
+
+// StreamDirByReadDir - функция для обхода директории с передачей элементов в канал по мере готовности.
+// Канал закрывается после обработки всех элементов.
+func StreamDirByReadDir(ctx context.Context, path string, opts Options, out chan<- FileInfo) error {
+	defer close(out)
+	return scanDir(ctx, path, opts, nil, func(fileInfo FileInfo) {
+		out <- fileInfo
+	})
+}
+
+// scanJob - элемент директории, ожидающий обработки в пуле scanDir.
+type scanJob struct {
+	entry   os.DirEntry
+	newPath string
+	name    string
+}
+
+// scanDir - функция для обхода директории. Элементы обрабатываются пулом из opts.Workers горутин,
+// каждый готовый элемент передается в emit из горутины пула. Если skipped не nil, в него записываются
+// сведения о скрытых элементах. После отмены ctx новые элементы в пул не передаются,
+// а подсчет размеров в уже обрабатываемых прерывается.
+func scanDir(ctx context.Context, path string, opts Options, skipped *SkipStats, emit func(FileInfo)) error {
+	var wg sync.WaitGroup
 
-	for _, val := range filesAndDirs {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	jobs := make(chan scanJob)
+	for i := 0; i < workers; i++ {
 		wg.Add(1)
-		go func(val os.DirEntry) {
+		go func() {
 			defer wg.Done()
-			newPath := filepath.Join(path, val.Name())
-			fileInfo := FileInfo{
-				Name:  val.Name(),
-				IsDir: val.IsDir(),
-				Path:  newPath,
+			for job := range jobs {
+				if fileInfo, ok := scanEntry(ctx, job.entry, job.newPath, job.name, opts); ok {
+					emit(fileInfo)
+				}
+			}
+		}()
+	}
+
+	// readErrors - ошибки чтения, накопленные в режиме PartialOnError.
+	// listDir вызывается рекурсивно в одной горутине, поэтому блокировка не нужна.
+	var readErrors []error
+
+	// listDir - функция для чтения одного уровня, при необходимости спускается во вложенные директории.
+	var listDir func(dir, relDir string, level int) error
+	listDir = func(dir, relDir string, level int) error {
+		// Читаем содержимое текущей директории.
+		filesAndDirs, err := os.ReadDir(dir)
+		if err != nil {
+			opts.logger().Error("ошибка чтения директории:", err)
+			if !opts.PartialOnError {
+				return err
+			}
+			// os.ReadDir возвращает элементы, прочитанные до ошибки, - выводим их.
+			readErrors = append(readErrors, err)
+		}
+
+		// Скрываем элементы, перечисленные в .filesystem-ignore, и сам этот файл.
+		ignore := loadIgnoreRules(dir)
+
+		limited := false
+		for _, val := range filesAndDirs {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+			if val.Name() == IgnoreFileName {
+				continue
+			}
+			if ignore.match(val.Name(), val.IsDir()) {
+				if skipped != nil {
+					skipped.Count++
+					if info, err := val.Info(); err == nil && !val.IsDir() {
+						skipped.Bytes += float64(info.Size())
+					}
+				}
+				continue
+			}
+			newPath := filepath.Join(dir, val.Name())
+			name := filepath.Join(relDir, val.Name())
+			if val.IsDir() && opts.SkipDir != nil && opts.SkipDir(newPath) {
+				continue
+			}
+
+			// Для вложенных уровней имя выводим относительно корня сканирования.
+			if val.IsDir() && (opts.ListDepth <= 0 || level < opts.ListDepth) && !opts.SkipMounts[newPath] {
+				_ = listDir(newPath, name, level+1)
 			}
 
-			if val.IsDir() {
-				// Для директорий вычисляем размер рекурсивно.
-				size := GetDirSize(newPath)
-				fileInfo.Size = size
-			} else {
-				info, err := val.Info()
-				if err != nil {
-					fmt.Println("ошибка получения информации о файле:", err)
-					return
+			// При превышении лимита горутин в процессе оставшиеся элементы обрабатываем без пула.
+			if !limited && opts.MaxGoroutines > 0 && runtime.NumGoroutine() >= opts.MaxGoroutines {
+				limited = true
+				atomic.AddInt64(&goroutineLimitHits, 1)
+				opts.logger().Info("предупреждение: превышен лимит горутин, элементы обрабатываются последовательно:", dir)
+			}
+			if limited {
+				if fileInfo, ok := scanEntry(ctx, val, newPath, name, opts); ok {
+					emit(fileInfo)
 				}
-				fileInfo.Size = float64(info.Size())
+				continue
 			}
 
-			mu.Lock()
-			fileList = append(fileList, fileInfo)
-			mu.Unlock()
-		}(val)
+			select {
+			case jobs <- scanJob{entry: val, newPath: newPath, name: name}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
 	}
 
+	// Дожидаемся пула и при ошибке, чтобы emit не вызывался после возврата.
+	err := listDir(path, "", 1)
+	close(jobs)
 	wg.Wait()
-	return fileList, nil
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(readErrors) > 0 {
+		return &PartialError{Errors: readErrors}
+	}
+	return nil
 }
 
-// GetDirSize - функция для вычисления размера директории.
-func GetDirSize(path string) float64 {
-	var size int64
+// scanEntry - функция для сбора информации об одном элементе директории.
+// Возвращает false, если информацию получить не удалось.
+func scanEntry(ctx context.Context, val os.DirEntry, newPath, name string, opts Options) (FileInfo, bool) {
+	fileInfo := entryInfo(val, newPath, name)
 
-	// Рекурсивно обходим все файлы и поддиректории.
-	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+	if val.IsDir() {
+		fileInfo.MIMEType = MIMEDirectory
+		// Для директорий вычисляем размер рекурсивно, пропущенные точки монтирования не обходим.
+		if opts.SkipMounts[newPath] {
+			opts.logger().Info("предупреждение: пропущена сетевая точка монтирования:", newPath)
+			return fileInfo, true
+		}
+		if opts.FastSize {
+			size, exact := GetDirSizeFast(ctx, newPath, opts)
+			fileInfo.Size = size
+			fileInfo.SizeEstimate = !exact
+			return fileInfo, true
+		}
+		fileInfo.Size, fileInfo.FileCount = GetDirSizeAndCount(ctx, newPath, opts)
+	} else {
+		info, err := val.Info()
 		if err != nil {
-			return err
+			opts.logger().Error("ошибка получения информации о файле:", err)
+			return fileInfo, false
 		}
-		if info.IsDir() {
-			// Для каждой директории добавляем 4096 байт (размер метаданных).
-			if info.Name() != filepath.Base(path) {
-				size += info.Size()
-			}
-		} else {
-			// Для файлов добавляем их размер.
-			size += info.Size()
+		fileInfo.Size = float64(info.Size())
+		fileInfo.IsExecutable = isExecutable(newPath, info.Mode())
+		// Содержимое читаем только у обычных файлов, чтобы не блокироваться на каналах и устройствах.
+		if info.Mode().IsRegular() {
+			fileInfo.MIMEType = DetectMIMEType(newPath)
+		}
+	}
+
+	return fileInfo, true
+}
+
+// EntryInfo - функция для получения сведений об элементе директории path без чтения содержимого:
+// имя, время изменения, права, сведения о ссылке и, для файлов, размер. Размер директорий не считается.
+func EntryInfo(path string, val os.DirEntry) FileInfo {
+	fileInfo := entryInfo(val, path, val.Name())
+	if !val.IsDir() {
+		if info, err := val.Info(); err == nil {
+			fileInfo.Size = float64(info.Size())
+		}
+	}
+	return fileInfo
+}
+
+// entryInfo - функция для заполнения сведений об элементе, не требующих обхода и чтения содержимого.
+func entryInfo(val os.DirEntry, newPath, name string) FileInfo {
+	fileInfo := FileInfo{
+		Name:  name,
+		IsDir: val.IsDir(),
+		Path:  newPath,
+	}
+
+	// Заменяем управляющие и недопустимые символы, чтобы они не попали в вывод.
+	var nameSanitised, pathSanitised bool
+	fileInfo.Name, nameSanitised = SanitiseName(fileInfo.Name)
+	fileInfo.Path, pathSanitised = SanitiseName(fileInfo.Path)
+	fileInfo.NameSanitised = nameSanitised || pathSanitised
+
+	if info, err := val.Info(); err == nil {
+		fileInfo.ModTime = info.ModTime()
+		fileInfo.Mode = info.Mode()
+		fileInfo.Permissions = modeString(info.Mode())
+	}
+
+	// DirEntry не разыменовывает ссылки, поэтому тип берем из него, а цель проверяем отдельно.
+	if val.Type()&os.ModeSymlink != 0 {
+		fileInfo.IsSymlink = true
+		if target, err := os.Readlink(newPath); err == nil {
+			fileInfo.SymlinkTarget, _ = SanitiseName(target)
+		}
+		if _, err := os.Stat(newPath); err != nil {
+			fileInfo.IsBroken = true
 		}
-		return nil
-	})
-	if err != nil {
-		fmt.Println("ошибка при вычислении размера директории:", err)
-		return 0
 	}
 
-	return float64(size)
+	return fileInfo
+}
+
+// goroutineLimitHits - количество случаев, когда сканирование переходило на последовательную обработку.
+var goroutineLimitHits int64
+
+// GoroutineLimitHits - функция для получения количества срабатываний лимита горутин.
+func GoroutineLimitHits() int64 {
+	return atomic.LoadInt64(&goroutineLimitHits)
+}
+
+// Ключи сортировки списка файлов.
+const (
+	SortBySize  = "size"  // SortBySize - по размеру.
+	SortByName  = "name"  // SortByName - по имени.
+	SortByMtime = "mtime" // SortByMtime - по времени изменения.
+	SortByExt   = "ext"   // SortByExt - по расширению, директории отдельной группой.
+)
+
+// SortOptions - структура с параметрами сортировки списка.
+type SortOptions struct {
+	Key       string // Key - ключ сортировки (SortBySize, SortByName, SortByMtime, SortByExt).
+	Order     string // Order - направление сортировки (asc или desc).
+	DirsFirst bool   // DirsFirst - выводить директории перед файлами независимо от ключа.
+	Locale    string // Locale - правило сравнения имен (SortLocaleOS, SortLocaleUnicode; пустое - побайтно).
+}
+
+// Правила сравнения имен для SortOptions.Locale.
+const (
+	SortLocaleOS      = "os"      // SortLocaleOS - без учета регистра (strings.ToLower).
+	SortLocaleUnicode = "unicode" // SortLocaleUnicode - сопоставление по правилам Unicode, с учетом букв с диакритикой.
+)
+
+// nameLess - метод для получения функции сравнения имен по правилу opts.Locale. Числа в именах
+// сравниваются как числа; имена, равные по правилу (например, отличающиеся только регистром),
+// упорядочиваются побайтно, чтобы порядок не зависел от порядка сканирования.
+func (opts SortOptions) nameLess() func(a, b string) bool {
+	compare := strings.Compare
+	switch opts.Locale {
+	case SortLocaleOS:
+		compare = func(a, b string) int {
+			return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+		}
+	case SortLocaleUnicode:
+		// Регистр, как и в os, не учитывается, а буквы с диакритикой сортируются рядом с базовыми.
+		// Collator не безопасен для параллельного использования, поэтому создается на каждую сортировку.
+		compare = collate.New(language.Und, collate.IgnoreCase).CompareString
+	}
+	return func(a, b string) bool {
+		if c := naturalCompare(a, b, compare); c != 0 {
+			return c < 0
+		}
+		return a < b
+	}
 }
 
 // SortFileList - функция для сортировки списка файлов и директорий.
-func SortFileList(fileList []FileInfo, sortType string) {
-	sort.Slice(fileList, func(i, j int) bool {
-		if sortType == "asc" {
-			return fileList[i].Size < fileList[j].Size
+func SortFileList(fileList []FileInfo, opts SortOptions) {
+	nameLess := opts.nameLess()
+	less := func(a, b FileInfo) bool {
+		switch opts.Key {
+		case SortByName:
+			return nameLess(a.Name, b.Name)
+		case SortByMtime:
+			return a.ModTime.Before(b.ModTime)
+		case SortByExt:
+			return extLess(a, b, nameLess)
+		default:
+			return a.Size < b.Size
+		}
+	}
+	sort.SliceStable(fileList, func(i, j int) bool {
+		// Директории отделяем от файлов до применения ключа, направление на группы не влияет.
+		if opts.DirsFirst && fileList[i].IsDir != fileList[j].IsDir {
+			return fileList[i].IsDir
+		}
+		if opts.Order == "asc" {
+			return less(fileList[i], fileList[j])
 		} else {
-			return fileList[i].Size > fileList[j].Size
+			return less(fileList[j], fileList[i])
 		}
 	})
 }
 
-// ConvertSize - функция для перевода размера в байтах в кб/мб/гб/тб
-func ConvertSize(size float64) (float64, string) {
-	counter := 0
-	var value string
-	for {
-		if size >= 1000 {
-			size = size / 1000
-			counter += 1
-		} else {
-			break
+// extLess - функция сравнения по расширению: директории идут отдельной группой перед файлами,
+// файлы - по расширению, при равных расширениях - по имени.
+func extLess(a, b FileInfo, nameLess func(a, b string) bool) bool {
+	if a.IsDir != b.IsDir {
+		return a.IsDir
+	}
+	if !a.IsDir {
+		extA, extB := filepath.Ext(a.Name), filepath.Ext(b.Name)
+		if extA != extB {
+			return extA < extB
+		}
+	}
+	return nameLess(a.Name, b.Name)
+}
+
+// naturalLess - функция для естественного сравнения имен: последовательности цифр сравниваются
+// как числа, поэтому file2 идет раньше file10. Остальные части сравниваются посимвольно.
+func naturalLess(a, b string) bool {
+	return naturalCompare(a, b, strings.Compare) < 0
+}
+
+// naturalCompare - функция для естественного сравнения имен, в которой части без цифр сравниваются
+// функцией compare. Возвращает -1, 0 или 1, как strings.Compare.
+func naturalCompare(a, b string, compare func(a, b string) int) int {
+	for a != "" && b != "" {
+		segA, restA := nextSegment(a)
+		segB, restB := nextSegment(b)
+		if isDigit(segA[0]) && isDigit(segB[0]) {
+			// Числа сравниваем без ведущих нулей: сначала по количеству цифр, затем посимвольно,
+			// чтобы не переполнять int на длинных последовательностях.
+			numA, numB := strings.TrimLeft(segA, "0"), strings.TrimLeft(segB, "0")
+			if len(numA) != len(numB) {
+				if len(numA) < len(numB) {
+					return -1
+				}
+				return 1
+			}
+			if c := strings.Compare(numA, numB); c != 0 {
+				return c
+			}
+		} else if c := compare(segA, segB); c != 0 {
+			return c
 		}
+		a, b = restA, restB
+	}
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return -1
+	default:
+		return 1
+	}
+}
+
+// nextSegment - функция для выделения из начала строки последовательности цифр или остальных символов.
+func nextSegment(s string) (string, string) {
+	digits := isDigit(s[0])
+	i := 1
+	for i < len(s) && isDigit(s[i]) == digits {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+// isDigit - функция для проверки, является ли байт десятичной цифрой.
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// ConvertSize - функция для перевода размера в байтах в кб/мб/гб/тб.
+// При binary единицы двоичные (КиБ, МиБ, ГиБ, ТиБ, шаг 1024), иначе десятичные (шаг 1000).
+// Единица измерения возвращается ключом каталога сообщений (например, "unit.kilobytes" или "unit.kibibytes").
+func ConvertSize(size float64, binary bool) (float64, string) {
+	units := decimalUnits
+	step := 1000.0
+	if binary {
+		units = binaryUnits
+		step = 1024
 	}
-	switch counter {
-	case 0:
-		value = "байт"
-	case 1:
-		value = "килобайт"
-	case 2:
-		value = "мегабайт"
-	case 3:
-		value = "гигабайт"
-	case 4:
-		value = "терабайт"
+	counter := 0
+	for size >= step && counter < len(units)-1 {
+		size = size / step
+		counter += 1
 	}
 	roundedSize := math.Round(size*10) / 10
-	return roundedSize, value
+	return roundedSize, units[counter]
+}
+
+// decimalUnits, binaryUnits - ключи каталога сообщений для единиц размера по порядку возрастания.
+var (
+	decimalUnits = []string{"unit.bytes", "unit.kilobytes", "unit.megabytes", "unit.gigabytes", "unit.terabytes"}
+	binaryUnits  = []string{"unit.bytes", "unit.kibibytes", "unit.mebibytes", "unit.gibibytes", "unit.tebibytes"}
+)
+
+// ScanDiff - структура для хранения изменений между двумя сканированиями одной директории.
+type ScanDiff struct {
+	Added   []string   // Added - имена появившихся файлов и директорий.
+	Removed []string   // Removed - имена удаленных файлов и директорий.
+	Grown   []FileInfo // Grown - элементы, размер которых увеличился.
+	Shrunk  []FileInfo // Shrunk - элементы, размер которых уменьшился.
+}
+
+// DiffFileLists - функция для сравнения предыдущего и текущего результатов сканирования.
+func DiffFileLists(prev, cur []FileInfo) ScanDiff {
+	var diff ScanDiff
+
+	prevByName := make(map[string]FileInfo, len(prev))
+	for _, val := range prev {
+		prevByName[val.Name] = val
+	}
+
+	curNames := make(map[string]bool, len(cur))
+	for _, val := range cur {
+		curNames[val.Name] = true
+		old, ok := prevByName[val.Name]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, val.Name)
+		case val.Size > old.Size:
+			diff.Grown = append(diff.Grown, val)
+		case val.Size < old.Size:
+			diff.Shrunk = append(diff.Shrunk, val)
+		}
+	}
+
+	for _, val := range prev {
+		if !curNames[val.Name] {
+			diff.Removed = append(diff.Removed, val.Name)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	return diff
+}
+
+// DirComparison - результат сравнения содержимого двух директорий.
+type DirComparison struct {
+	Added   []FileInfo // Added - элементы, которые есть только во второй директории.
+	Removed []FileInfo // Removed - элементы, которые есть только в первой директории.
+	Changed []FileInfo // Changed - элементы второй директории, у которых отличается размер или время изменения.
+}
+
+// CompareDirs - функция для сравнения списков двух директорий по именам элементов, например резервной
+// копии и рабочей директории. Все списки результата отсортированы по имени.
+func CompareDirs(a, b []FileInfo) DirComparison {
+	result := DirComparison{Added: []FileInfo{}, Removed: []FileInfo{}, Changed: []FileInfo{}}
+
+	aByName := make(map[string]FileInfo, len(a))
+	for _, val := range a {
+		aByName[val.Name] = val
+	}
+	bNames := make(map[string]bool, len(b))
+	for _, val := range b {
+		bNames[val.Name] = true
+		old, ok := aByName[val.Name]
+		switch {
+		case !ok:
+			result.Added = append(result.Added, val)
+		case val.Size != old.Size || !val.ModTime.Equal(old.ModTime):
+			result.Changed = append(result.Changed, val)
+		}
+	}
+	for _, val := range a {
+		if !bNames[val.Name] {
+			result.Removed = append(result.Removed, val)
+		}
+	}
+
+	for _, list := range [][]FileInfo{result.Added, result.Removed, result.Changed} {
+		sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	}
+	return result
 }
@@ -0,0 +1,70 @@
+package filesystem
+
+import "testing"
+
+func TestNaturalLess(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"file2", "file10", true},
+		{"file10", "file2", false},
+		{"a2b9", "a2b10", true},
+		{"a2b10", "a2b9", false},
+		{"a10b1", "a9b2", false},
+		// Ведущие нули не влияют на значение числа.
+		{"img007", "img7", false},
+		{"img7", "img007", false},
+		{"img010", "img9", false},
+		{"img9", "img010", true},
+		// Имена только из цифр, в том числе длиннее int64.
+		{"9", "10", true},
+		{"10", "9", false},
+		{"123", "123", false},
+		{"99999999999999999999", "100000000000000000000", true},
+		{"100000000000000000000", "99999999999999999999", false},
+		// Цифры и прочие символы, префиксы.
+		{"1a", "a", true},
+		{"file", "file1", true},
+		{"file1", "file", false},
+		{"", "a", true},
+		{"a", "", false},
+	}
+
+	for _, tt := range tests {
+		if got := naturalLess(tt.a, tt.b); got != tt.want {
+			t.Errorf("naturalLess(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSortFileListLocale(t *testing.T) {
+	names := []string{"file10", "Zebra", "éclair", "apple", "File2", "zoo", "ñandu", "eclair", "nube", "Eagle", "file2"}
+	tests := []struct {
+		locale string
+		want   []string
+	}{
+		{"", []string{"Eagle", "File2", "Zebra", "apple", "eclair", "file2", "file10", "nube", "zoo", "éclair", "ñandu"}},
+		{SortLocaleOS, []string{"apple", "Eagle", "eclair", "File2", "file2", "file10", "nube", "Zebra", "zoo", "éclair", "ñandu"}},
+		{SortLocaleUnicode, []string{"apple", "Eagle", "eclair", "éclair", "File2", "file2", "file10", "ñandu", "nube", "Zebra", "zoo"}},
+	}
+
+	for _, tt := range tests {
+		fileList := make([]FileInfo, len(names))
+		for i, name := range names {
+			fileList[i] = FileInfo{Name: name}
+		}
+		SortFileList(fileList, SortOptions{Key: SortByName, Order: "asc", Locale: tt.locale})
+
+		got := make([]string, len(fileList))
+		for i, fileInfo := range fileList {
+			got[i] = fileInfo.Name
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("locale %q: order = %q, want %q", tt.locale, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,23 @@
+//go:build !windows
+
+package filesystem
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileID - идентификатор файла в системе: устройство и inode.
+type fileID struct {
+	dev uint64
+	ino uint64
+}
+
+// getFileID - функция для получения идентификатора файла из os.FileInfo.
+func getFileID(info os.FileInfo) (fileID, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileID{}, false
+	}
+	return fileID{dev: uint64(stat.Dev), ino: uint64(stat.Ino)}, true
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package filesystem
+
+import "os"
+
+// fileID - идентификатор файла в системе. В Windows не поддерживается.
+type fileID struct {
+	dev uint64
+	ino uint64
+}
+
+// getFileID - функция для получения идентификатора файла. В Windows идентификатор недоступен,
+// поэтому циклы из символических ссылок не отслеживаются.
+func getFileID(info os.FileInfo) (fileID, bool) {
+	return fileID{}, false
+}
@@ -0,0 +1,90 @@
+package filesystem
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// gitCommitPrefix - маркер строки с данными коммита в выводе git log (git выводит его вместо %x00).
+const gitCommitPrefix = "\x00"
+
+// gitLogFormat - формат строки коммита: автор, почта и время, разделенные нулевым байтом.
+const gitLogFormat = "%x00%an%x00%ae%x00%aI"
+
+// AddGitInfo - функция для заполнения сведений о последнем коммите для элементов директории,
+// находящейся в git-репозитории. История читается одним вызовом git log, от новых коммитов к старым.
+func AddGitInfo(dir string, fileList []FileInfo) error {
+	if len(fileList) == 0 {
+		return nil
+	}
+
+	cmd := exec.Command("git", "-C", dir, "-c", "core.quotepath=off", "log",
+		"--relative", "--name-only", "--format="+gitLogFormat, "--", ".")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	// Индексы элементов списка по имени относительно директории.
+	pending := make(map[string]int, len(fileList))
+	for i, val := range fileList {
+		pending[filepath.ToSlash(val.Name)] = i
+	}
+
+	var author, email string
+	var commitTime time.Time
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() && len(pending) > 0 {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, gitCommitPrefix) {
+			parts := strings.SplitN(strings.TrimPrefix(line, gitCommitPrefix), gitCommitPrefix, 3)
+			if len(parts) != 3 {
+				continue
+			}
+			author, email = parts[0], parts[1]
+			commitTime, _ = time.Parse(time.RFC3339, parts[2])
+			continue
+		}
+
+		// Изменение файла относится и к нему самому, и ко всем директориям на пути к нему.
+		for name := line; name != "." && name != ""; name = dirName(name) {
+			if i, ok := pending[name]; ok {
+				fileList[i].LastCommitAuthor = author
+				fileList[i].LastCommitEmail = email
+				fileList[i].LastCommitTime = commitTime
+				delete(pending, name)
+			}
+		}
+	}
+
+	// Если все элементы заполнены раньше конца истории, дальше git не читаем.
+	if len(pending) == 0 {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return nil
+	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("ошибка выполнения git log: %w", err)
+	}
+	return scanner.Err()
+}
+
+// dirName - функция для получения родительской директории пути с разделителями "/".
+func dirName(name string) string {
+	i := strings.LastIndex(name, "/")
+	if i < 0 {
+		return ""
+	}
+	return name[:i]
+}
@@ -0,0 +1,69 @@
+package filesystem
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFileName - имя файла со списком скрываемых элементов директории.
+const IgnoreFileName = ".filesystem-ignore"
+
+// ignoreRule - одно правило из файла .filesystem-ignore.
+type ignoreRule struct {
+	pattern string // pattern - шаблон имени в синтаксисе filepath.Match.
+	dirOnly bool   // dirOnly - правило относится только к директориям (шаблон оканчивался на "/").
+	negate  bool   // negate - правило возвращает видимость (шаблон начинался с "!").
+}
+
+// ignoreRules - набор правил директории. Как и в .gitignore, побеждает последнее подходящее правило.
+type ignoreRules []ignoreRule
+
+// loadIgnoreRules - функция для чтения правил из файла .filesystem-ignore в директории.
+// Пустые строки и строки, начинающиеся с "#", пропускаются. Если файла нет, правил нет.
+func loadIgnoreRules(dir string) ignoreRules {
+	file, err := os.Open(filepath.Join(dir, IgnoreFileName))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var rules ignoreRules
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var rule ignoreRule
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		rule.pattern = strings.TrimPrefix(line, "/")
+		if rule.pattern != "" {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// match - метод для проверки, скрыт ли элемент с указанным именем.
+func (rules ignoreRules) match(name string, isDir bool) bool {
+	ignored := false
+	for _, rule := range rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if ok, _ := filepath.Match(rule.pattern, name); ok {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
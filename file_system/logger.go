@@ -0,0 +1,36 @@
+package filesystem
+
+import "fmt"
+
+// Logger - журнал, в который пакет пишет предупреждения и ошибки сканирования.
+// Журнал сервера удовлетворяет этому интерфейсу.
+type Logger interface {
+	Info(args ...interface{})  // Info - запись о штатном событии или предупреждение.
+	Error(args ...interface{}) // Error - запись об ошибке.
+}
+
+// stdoutLogger - журнал по умолчанию, печатающий записи в stdout.
+type stdoutLogger struct{}
+
+// Info - метод для вывода предупреждения.
+func (stdoutLogger) Info(args ...interface{}) {
+	fmt.Println(args...)
+}
+
+// Error - метод для вывода ошибки.
+func (stdoutLogger) Error(args ...interface{}) {
+	fmt.Println(args...)
+}
+
+// loggerOrDefault - функция для получения журнала logger или stdoutLogger, если он не задан.
+func loggerOrDefault(logger Logger) Logger {
+	if logger == nil {
+		return stdoutLogger{}
+	}
+	return logger
+}
+
+// logger - метод для получения журнала из параметров сканирования.
+func (opts Options) logger() Logger {
+	return loggerOrDefault(opts.Logger)
+}
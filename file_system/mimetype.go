@@ -0,0 +1,36 @@
+package filesystem
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// MIMEDirectory - тип содержимого, указываемый для директорий.
+const MIMEDirectory = "inode/directory"
+
+// sniffLen - сколько первых байт файла читается для определения типа, как в http.DetectContentType.
+const sniffLen = 512
+
+// DetectMIMEType - функция для определения типа содержимого файла: сначала по расширению,
+// а если расширение неизвестно - по первым байтам файла. Пустая строка, если файл не прочитан.
+func DetectMIMEType(path string) string {
+	if mimeType := mime.TypeByExtension(filepath.Ext(path)); mimeType != "" {
+		return mimeType
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	buf := make([]byte, sniffLen)
+	n, err := io.ReadFull(file, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return ""
+	}
+	return http.DetectContentType(buf[:n])
+}
@@ -0,0 +1,27 @@
+package filesystem
+
+import "os"
+
+// modeString - функция для вывода прав доступа в виде, привычном по ls -l: "-rwxr-xr-x", "drwxr-xr-x", "lrwxrwxrwx".
+// В отличие от os.FileMode.String, тип обозначается одной буквой, а особые биты не выводятся.
+func modeString(m os.FileMode) string {
+	kind := byte('-')
+	switch {
+	case m&os.ModeDir != 0:
+		kind = 'd'
+	case m&os.ModeSymlink != 0:
+		kind = 'l'
+	case m&os.ModeNamedPipe != 0:
+		kind = 'p'
+	case m&os.ModeSocket != 0:
+		kind = 's'
+	case m&os.ModeCharDevice != 0:
+		kind = 'c'
+	case m&os.ModeDevice != 0:
+		kind = 'b'
+	}
+	// Perm().String() возвращает "-rwxr-xr-x", первый символ заменяем типом.
+	perm := []byte(m.Perm().String())
+	perm[0] = kind
+	return string(perm)
+}
@@ -0,0 +1,36 @@
+package filesystem
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// networkFSTypes - типы файловых систем, относящиеся к сетевым.
+var networkFSTypes = map[string]bool{
+	"nfs":        true,
+	"nfs4":       true,
+	"cifs":       true,
+	"smbfs":      true,
+	"smb3":       true,
+	"afpfs":      true,
+	"webdav":     true,
+	"fuse.sshfs": true,
+	"9p":         true,
+}
+
+// MountSet - множество точек монтирования, ключом служит путь.
+type MountSet map[string]bool
+
+// Under - метод для получения точек монтирования, находящихся внутри root.
+func (m MountSet) Under(root string) []string {
+	root = filepath.Clean(root)
+	var result []string
+	for mountPoint := range m {
+		if mountPoint != root && strings.HasPrefix(mountPoint, strings.TrimSuffix(root, string(filepath.Separator))+string(filepath.Separator)) {
+			result = append(result, mountPoint)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
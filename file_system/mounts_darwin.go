@@ -0,0 +1,35 @@
+package filesystem
+
+import "syscall"
+
+// NetworkMounts - функция для получения сетевых точек монтирования через getfsstat.
+func NetworkMounts() (MountSet, error) {
+	count, err := syscall.Getfsstat(nil, 1) // 1 - MNT_WAIT.
+	if err != nil {
+		return nil, err
+	}
+	stats := make([]syscall.Statfs_t, count)
+	if _, err := syscall.Getfsstat(stats, 1); err != nil {
+		return nil, err
+	}
+
+	mounts := make(MountSet)
+	for _, stat := range stats {
+		if networkFSTypes[int8ToString(stat.Fstypename[:])] {
+			mounts[int8ToString(stat.Mntonname[:])] = true
+		}
+	}
+	return mounts, nil
+}
+
+// int8ToString - функция для перевода C-строки из структуры statfs в строку Go.
+func int8ToString(chars []int8) string {
+	buf := make([]byte, 0, len(chars))
+	for _, c := range chars {
+		if c == 0 {
+			break
+		}
+		buf = append(buf, byte(c))
+	}
+	return string(buf)
+}
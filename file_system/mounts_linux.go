@@ -0,0 +1,49 @@
+package filesystem
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// NetworkMounts - функция для получения сетевых точек монтирования из /proc/mounts.
+func NetworkMounts() (MountSet, error) {
+	file, err := os.Open("/proc/mounts")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	mounts := make(MountSet)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// Формат строки: устройство, точка монтирования, тип, опции, ...
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || !networkFSTypes[fields[2]] {
+			continue
+		}
+		mounts[unescapeMountPath(fields[1])] = true
+	}
+	return mounts, scanner.Err()
+}
+
+// unescapeMountPath - функция для раскодирования пробелов и спецсимволов, записанных в /proc/mounts
+// восьмеричными последовательностями вида \040.
+func unescapeMountPath(path string) string {
+	if !strings.Contains(path, `\`) {
+		return path
+	}
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' && i+4 <= len(path) {
+			if code, err := strconv.ParseUint(path[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(code))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(path[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package filesystem
+
+// NetworkMounts - функция для получения сетевых точек монтирования. На этой платформе не поддерживается.
+func NetworkMounts() (MountSet, error) {
+	return MountSet{}, nil
+}
@@ -0,0 +1,28 @@
+package filesystem
+
+import (
+	"regexp"
+	"runtime"
+)
+
+// invalidNameChars - выражение для поиска недопустимых символов в именах файлов:
+// управляющие символы (кроме табуляции и перевода строки) и символы, запрещенные в целевой ОС.
+var invalidNameChars = regexp.MustCompile(invalidNamePattern(runtime.GOOS))
+
+// invalidNamePattern - функция для построения выражения недопустимых символов для ОС.
+func invalidNamePattern(goos string) string {
+	pattern := `\x00-\x08\x0B-\x1F\x7F`
+	if goos == "windows" {
+		pattern += `<>:"|?*`
+	}
+	return "[" + pattern + "]"
+}
+
+// SanitiseName - функция для замены недопустимых символов в имени или пути на "?".
+// Возвращает очищенную строку и признак того, что замена была.
+func SanitiseName(name string) (string, bool) {
+	if !invalidNameChars.MatchString(name) {
+		return name, false
+	}
+	return invalidNameChars.ReplaceAllString(name, "?"), true
+}
@@ -0,0 +1,219 @@
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+)
+
+// GetDirSize - функция для вычисления размера директории. Обход прерывается при отмене контекста.
+func GetDirSize(ctx context.Context, path string, opts Options) float64 {
+	return GetDirSizeWithProgress(ctx, path, opts, nil)
+}
+
+// GetDirSizeAndCount - функция для вычисления размера директории и количества файлов в ней
+// (с учетом вложенных директорий, сами директории не считаются).
+func GetDirSizeAndCount(ctx context.Context, path string, opts Options) (float64, int) {
+	walker := &sizeWalker{
+		ctx:     ctx,
+		opts:    opts,
+		visited: make(map[fileID]bool),
+	}
+
+	size, _ := walker.run(path)
+	return size, walker.files
+}
+
+// progressEvery - через сколько файлов отправляется промежуточный размер.
+const progressEvery = 1000
+
+// GetDirSizeWithProgress - функция для вычисления размера директории с отправкой промежуточных
+// результатов в канал progress каждые progressEvery файлов. Отправка неблокирующая: если читатель
+// не успевает, значение пропускается. Обход прерывается при отмене контекста.
+func GetDirSizeWithProgress(ctx context.Context, path string, opts Options, progress chan<- int64) float64 {
+	walker := &sizeWalker{
+		ctx:      ctx,
+		opts:     opts,
+		progress: progress,
+		visited:  make(map[fileID]bool),
+	}
+
+	size, _ := walker.run(path)
+	return size
+}
+
+// ErrSizeLimitExceeded - ошибка, возвращаемая при превышении Options.MaxScanSize.
+var ErrSizeLimitExceeded = errors.New("превышен лимит размера сканирования")
+
+// GetDirSizeLimited - функция для вычисления размера директории, сообщающая о срабатывании лимита Options.MaxScanSize.
+// При превышении лимита возвращается размер, подсчитанный до остановки, и ErrSizeLimitExceeded.
+func GetDirSizeLimited(ctx context.Context, path string, opts Options) (float64, error) {
+	walker := &sizeWalker{
+		ctx:     ctx,
+		opts:    opts,
+		visited: make(map[fileID]bool),
+	}
+	return walker.run(path)
+}
+
+// sizeWalker - структура с состоянием обхода при вычислении размера.
+type sizeWalker struct {
+	ctx      context.Context
+	opts     Options
+	progress chan<- int64
+	size     int64
+	files    int
+	visited  map[fileID]bool // visited - пройденные директории, для поиска циклов из символических ссылок.
+}
+
+// run - метод для подсчета размера дерева path. При превышении лимита возвращается частичный размер.
+func (w *sizeWalker) run(path string) (float64, error) {
+	if err := w.walk(path, 0); err != nil {
+		if errors.Is(err, ErrSizeLimitExceeded) {
+			w.opts.logger().Info("предупреждение: подсчет размера остановлен по лимиту:", path)
+			return float64(w.size), err
+		}
+		// Отмена и истечение срока контекста - не ошибки обхода, о них сообщает вызывающий.
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return 0, err
+		}
+		atomic.AddInt64(&dirSizeErrors, 1)
+		w.opts.logger().Error("ошибка при вычислении размера директории:", err)
+		return 0, err
+	}
+	return float64(w.size), nil
+}
+
+// dirSizeErrors - количество подсчетов размера, прерванных ошибкой обхода.
+var dirSizeErrors int64
+
+// DirSizeErrors - функция для получения количества подсчетов размера, прерванных ошибкой обхода.
+func DirSizeErrors() int64 {
+	return atomic.LoadInt64(&dirSizeErrors)
+}
+
+// checkLimit - метод для проверки, не превышен ли лимит размера сканирования.
+func (w *sizeWalker) checkLimit() error {
+	if w.opts.MaxScanSize > 0 && w.size > w.opts.MaxScanSize {
+		return ErrSizeLimitExceeded
+	}
+	return nil
+}
+
+// walk - метод для обхода дерева с корнем root, находящимся на уровне rootLevel относительно начала подсчета.
+func (w *sizeWalker) walk(root string, rootLevel int) error {
+	// Рекурсивно обходим все файлы и поддиректории.
+	return filepath.WalkDir(root, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := w.ctx.Err(); err != nil {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		// Определяем уровень вложенности относительно корня подсчета.
+		level := rootLevel
+		if rel, relErr := filepath.Rel(root, filePath); relErr == nil && rel != "." {
+			level += strings.Count(rel, string(filepath.Separator)) + 1
+		}
+
+		if info.Mode()&fs.ModeSymlink != 0 && w.opts.FollowSymlinks {
+			if err := w.walkSymlink(filePath, info, level); err != nil {
+				return err
+			}
+			return w.checkLimit()
+		}
+
+		if info.IsDir() {
+			if level > 0 && w.opts.SkipMounts[filePath] {
+				w.opts.logger().Info("предупреждение: пропущена сетевая точка монтирования:", filePath)
+				return filepath.SkipDir
+			}
+			if w.opts.FollowSymlinks {
+				if id, ok := getFileID(info); ok {
+					w.visited[id] = true
+				}
+			}
+			// Для каждой директории добавляем 4096 байт (размер метаданных), кроме корня подсчета.
+			if level > 0 {
+				w.size += info.Size()
+			}
+			// Глубже заданного уровня не спускаемся.
+			if w.opts.SizeDepth > 0 && level >= w.opts.SizeDepth {
+				return filepath.SkipDir
+			}
+		} else {
+			// Для файлов добавляем их размер.
+			w.addFile(info.Size())
+		}
+		return w.checkLimit()
+	})
+}
+
+// walkSymlink - метод для учета символической ссылки: ссылки на директории разыменовываются,
+// если директория еще не пройдена, остальные ссылки учитываются собственным размером.
+func (w *sizeWalker) walkSymlink(linkPath string, linkInfo os.FileInfo, level int) error {
+	target, err := os.Stat(linkPath)
+	if err != nil || !target.IsDir() {
+		// Битые ссылки и ссылки на файлы учитываем как обычные файлы.
+		w.addFile(linkInfo.Size())
+		return nil
+	}
+
+	id, ok := getFileID(target)
+	if ok && w.visited[id] {
+		w.opts.logger().Info("предупреждение: пропущена циклическая символическая ссылка:", linkPath)
+		return nil
+	}
+	if ok {
+		w.visited[id] = true
+	}
+
+	if level > 0 {
+		w.size += target.Size()
+	}
+	if w.opts.SizeDepth > 0 && level >= w.opts.SizeDepth {
+		return nil
+	}
+
+	resolved, err := filepath.EvalSymlinks(linkPath)
+	if err != nil {
+		return err
+	}
+	return w.walkChildren(resolved, level)
+}
+
+// walkChildren - метод для обхода содержимого директории, сама директория уже учтена.
+func (w *sizeWalker) walkChildren(dir string, level int) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := w.walk(filepath.Join(dir, entry.Name()), level+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addFile - метод для учета размера файла и отправки промежуточного результата.
+func (w *sizeWalker) addFile(size int64) {
+	w.size += size
+	w.files++
+	if w.progress != nil && w.files%progressEvery == 0 {
+		select {
+		case w.progress <- w.size:
+		default:
+		}
+	}
+}
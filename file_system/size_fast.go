@@ -0,0 +1,28 @@
+package filesystem
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// GetDirSizeFast - функция для быстрой оценки размера директории через du -s --bytes, который
+// использует кэш ядра и работает заметно быстрее обхода. Оценка не учитывает .filesystem-ignore,
+// лимиты глубины и пропуск точек монтирования, поэтому exact равен false. Если du недоступен
+// (Windows, нет в PATH или не поддерживает --bytes), размер вычисляется полным обходом и exact равен true.
+func GetDirSizeFast(ctx context.Context, path string, opts Options) (size float64, exact bool) {
+	out, err := exec.CommandContext(ctx, "du", "-s", "--bytes", path).Output()
+	if err == nil {
+		if fields := strings.Fields(string(out)); len(fields) > 0 {
+			if bytes, err := strconv.ParseFloat(fields[0], 64); err == nil {
+				return bytes, false
+			}
+		}
+	}
+	if ctx.Err() != nil {
+		return 0, false
+	}
+	opts.logger().Info("предупреждение: du недоступен, размер вычисляется обходом:", path)
+	return GetDirSize(ctx, path, opts), true
+}
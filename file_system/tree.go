@@ -0,0 +1,172 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Элементы рисунка дерева, как в выводе команды tree(1).
+const (
+	treeBranch   = "├── "
+	treeLast     = "└── "
+	treeIndent   = "│   "
+	treeLastPass = "    "
+)
+
+// WriteTree - функция для вывода дерева директории root в текстовом виде, как команда tree(1).
+// depth ограничивает количество выводимых уровней (0 - без ограничения). Элементы, скрытые через
+// .filesystem-ignore, и директории, отклоненные opts.SkipDir, не выводятся. Строки пишутся в w по мере
+// обхода; при отмене ctx вывод обрывается с ошибкой контекста.
+func WriteTree(ctx context.Context, w io.Writer, root string, depth int, opts Options) error {
+	info, err := os.Stat(root)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s не является директорией", root)
+	}
+
+	if _, err := fmt.Fprintf(w, "%s/\n", filepath.Clean(root)); err != nil {
+		return err
+	}
+
+	var dirs, files int
+	// writeLevel - функция для вывода содержимого директории. prefix - отступ, накопленный
+	// родительскими уровнями: "│   " под незавершенными ветками и пробелы под последними.
+	var writeLevel func(dir, prefix string, level int) error
+	writeLevel = func(dir, prefix string, level int) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			opts.logger().Error("ошибка чтения директории:", err)
+			return nil
+		}
+
+		ignore := loadIgnoreRules(dir)
+		visible := entries[:0]
+		for _, entry := range entries {
+			if entry.Name() == IgnoreFileName || ignore.match(entry.Name(), entry.IsDir()) {
+				continue
+			}
+			if entry.IsDir() && opts.SkipDir != nil && opts.SkipDir(filepath.Join(dir, entry.Name())) {
+				continue
+			}
+			visible = append(visible, entry)
+		}
+
+		for i, entry := range visible {
+			last := i == len(visible)-1
+			connector, childPrefix := treeBranch, prefix+treeIndent
+			if last {
+				connector, childPrefix = treeLast, prefix+treeLastPass
+			}
+
+			name, _ := SanitiseName(entry.Name())
+			if entry.IsDir() {
+				dirs++
+				name += "/"
+			} else {
+				files++
+			}
+			if _, err := fmt.Fprintf(w, "%s%s%s\n", prefix, connector, name); err != nil {
+				return err
+			}
+
+			if entry.IsDir() && (depth <= 0 || level < depth) {
+				if err := writeLevel(filepath.Join(dir, entry.Name()), childPrefix, level+1); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := writeLevel(root, "", 1); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "\n%d directories, %d files\n", dirs, files)
+	return err
+}
+
+// TreeNode - узел дерева директории для вывода в JSON.
+type TreeNode struct {
+	Name     string      `json:"name"`               // Name - имя элемента.
+	Path     string      `json:"path"`               // Path - полный путь к элементу.
+	IsDir    bool        `json:"isDir"`              // IsDir - является ли директорией.
+	Size     float64     `json:"size"`               // Size - размер в байтах (для директорий - с содержимым).
+	Children []*TreeNode `json:"children,omitempty"` // Children - содержимое директории, если ее уровень выводится.
+}
+
+// BuildTree - функция для построения дерева директории path. Содержимое выводится для depth уровней
+// (0 - без ограничения); размер директорий глубже считается через GetDirSize, а раскрытых -
+// суммированием потомков, чтобы не обходить одно поддерево несколько раз. Элементы, скрытые через
+// .filesystem-ignore, и директории, отклоненные opts.SkipDir, не выводятся. Обход прерывается при
+// отмене контекста.
+func BuildTree(ctx context.Context, path string, depth int, opts Options) (*TreeNode, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s не является директорией", path)
+	}
+
+	name, _ := SanitiseName(info.Name())
+	root := &TreeNode{Name: name, Path: filepath.Clean(path), IsDir: true}
+	if err := buildTreeLevel(ctx, root, depth, 1, opts); err != nil {
+		return nil, err
+	}
+	return root, ctx.Err()
+}
+
+// buildTreeLevel - функция для заполнения потомков директории node, находящейся на уровне level.
+func buildTreeLevel(ctx context.Context, node *TreeNode, depth, level int, opts Options) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(node.Path)
+	if err != nil {
+		opts.logger().Error("ошибка чтения директории:", err)
+		return nil
+	}
+
+	ignore := loadIgnoreRules(node.Path)
+	for _, entry := range entries {
+		if entry.Name() == IgnoreFileName || ignore.match(entry.Name(), entry.IsDir()) {
+			continue
+		}
+		childPath := filepath.Join(node.Path, entry.Name())
+		if entry.IsDir() && opts.SkipDir != nil && opts.SkipDir(childPath) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		name, _ := SanitiseName(entry.Name())
+		child := &TreeNode{Name: name, Path: childPath, IsDir: entry.IsDir()}
+		switch {
+		case !child.IsDir:
+			child.Size = float64(info.Size())
+		case depth <= 0 || level < depth:
+			if err := buildTreeLevel(ctx, child, depth, level+1, opts); err != nil {
+				return err
+			}
+		default:
+			child.Size = GetDirSize(ctx, child.Path, opts)
+		}
+		// Как и GetDirSize, учитываем собственный размер вложенных директорий.
+		node.Size += child.Size
+		if child.IsDir {
+			node.Size += float64(info.Size())
+		}
+		node.Children = append(node.Children, child)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// gzipTestBody - тело ответа для тестов сжатия, похожее на JSON-список файлов.
+var gzipTestBody = []byte(strings.Repeat(`{"Name":"file.txt","Size":1024,"Unit":"байт","IsDir":false},`, 500))
+
+// gzipTestHandler - обработчик, отдающий gzipTestBody как JSON.
+var gzipTestHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(gzipTestBody)
+})
+
+func TestGzipMiddleware(t *testing.T) {
+	handler := gzipMiddleware(gzipTestHandler)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	gz, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(body, gzipTestBody) {
+		t.Fatal("decompressed body differs from the original")
+	}
+
+	// Без Accept-Encoding ответ передается как есть.
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Fatalf("Content-Encoding = %q without Accept-Encoding", got)
+	}
+	if !bytes.Equal(rec.Body.Bytes(), gzipTestBody) {
+		t.Fatal("plain body differs from the original")
+	}
+}
+
+func BenchmarkGzipMiddleware(b *testing.B) {
+	handler := gzipMiddleware(gzipTestHandler)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(gzipTestBody)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
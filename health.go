@@ -0,0 +1,69 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"os"
+	"runtime"
+	"time"
+)
+
+// HealthResponse - структура ответа проверки работоспособности. Подробные поля выводятся
+// только при --health-verbose, чтобы не раскрывать сведения о сервере без авторизации.
+type HealthResponse struct {
+	Status     string  `json:"status"`               // Status - состояние сервера.
+	Uptime     string  `json:"uptime,omitempty"`     // Uptime - время работы сервера.
+	Goroutines int     `json:"goroutines,omitempty"` // Goroutines - количество горутин.
+	HeapMB     float64 `json:"heapMB,omitempty"`     // HeapMB - занятая память кучи в мегабайтах.
+	CgoCalls   *int64  `json:"cgoCalls,omitempty"`   // CgoCalls - количество вызовов cgo.
+}
+
+// handleHealthLive - функция-обработчик проверки того, что сервер запущен и отвечает.
+func handleHealthLive(w http.ResponseWriter, r *http.Request) {
+	response := HealthResponse{Status: "ok"}
+	if config.HealthVerbose {
+		var mem runtime.MemStats
+		runtime.ReadMemStats(&mem)
+		cgoCalls := runtime.NumCgoCall()
+
+		response.Uptime = time.Since(stats.startTime).Round(time.Second).String()
+		response.Goroutines = runtime.NumGoroutine()
+		response.HeapMB = math.Round(float64(mem.HeapAlloc)/1e6*10) / 10
+		response.CgoCalls = &cgoCalls
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleHealthz - функция-обработчик проверки живости для Kubernetes и балансировщиков.
+// Не обращается к файловой системе и доступен без входа.
+func handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, HealthResponse{
+		Status: "ok",
+		Uptime: time.Since(stats.startTime).Round(time.Second).String(),
+	})
+}
+
+// handleReadyz - функция-обработчик проверки готовности. Если задан --default-root, сервер готов,
+// только когда эта директория доступна; иначе ответ совпадает с /healthz.
+func handleReadyz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	if config.DefaultRoot != "" {
+		if _, err := os.Stat(config.DefaultRoot); err != nil {
+			writeJSON(w, http.StatusServiceUnavailable, HealthResponse{Status: "unavailable"})
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, HealthResponse{
+		Status: "ok",
+		Uptime: time.Since(stats.startTime).Round(time.Second).String(),
+	})
+}
+
+// isProbePath - функция для проверки, относится ли путь к проверкам живости и готовности,
+// которые должны быть доступны без входа.
+func isProbePath(path string) bool {
+	return path == "/healthz" || path == "/readyz"
+}
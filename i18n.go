@@ -0,0 +1,71 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/text/language"
+)
+
+// i18nFS - встроенные каталоги сообщений интерфейса.
+//
+//go:embed web/i18n/*.json
+var i18nFS embed.FS
+
+// supportedLangs - языки, для которых есть каталоги сообщений.
+var supportedLangs = []string{"ru", "en", "de"}
+
+// catalogs - каталоги сообщений по языкам.
+var catalogs = map[string]map[string]string{}
+
+// loadMessages - функция для загрузки каталога сообщений для указанного языка.
+func loadMessages(lang string) (map[string]string, error) {
+	data, err := i18nFS.ReadFile("web/i18n/" + lang + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("язык %q не поддерживается", lang)
+	}
+
+	var catalog map[string]string
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("ошибка чтения каталога сообщений %q: %w", lang, err)
+	}
+	return catalog, nil
+}
+
+// loadCatalogs - функция для загрузки каталогов всех поддерживаемых языков.
+func loadCatalogs() error {
+	for _, lang := range supportedLangs {
+		catalog, err := loadMessages(lang)
+		if err != nil {
+			return err
+		}
+		catalogs[lang] = catalog
+	}
+	return nil
+}
+
+// resolveLang - функция для выбора языка ответа по заголовку Accept-Language.
+// Если заголовка нет или ни один язык не поддерживается, используется язык сервера (--lang).
+func resolveLang(r *http.Request) string {
+	tags, _, err := language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	if err != nil {
+		return config.Lang
+	}
+	for _, tag := range tags {
+		base, _ := tag.Base()
+		if _, ok := catalogs[base.String()]; ok {
+			return base.String()
+		}
+	}
+	return config.Lang
+}
+
+// translate - функция для получения перевода по ключу для языка. Если перевода нет, возвращается сам ключ.
+func translate(lang, key string) string {
+	if msg, ok := catalogs[lang][key]; ok {
+		return msg
+	}
+	return key
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultJournalMaxSize - ограничение --journal-max-size по умолчанию.
+const defaultJournalMaxSize = 100_000_000
+
+// defaultJournalKeep - количество сохраняемых старых журналов для --journal-keep по умолчанию.
+const defaultJournalKeep = 5
+
+// Операции и состояния записей журнала изменений.
+const (
+	JournalOpMove = "MOVE" // JournalOpMove - перемещение или переименование файла.
+
+	JournalStatusOK     = "ok"     // JournalStatusOK - операция выполнена.
+	JournalStatusFailed = "failed" // JournalStatusFailed - операция завершилась ошибкой.
+)
+
+// JournalEntry - запись журнала изменений файловой системы.
+type JournalEntry struct {
+	ID        string    `json:"id"`            // ID - идентификатор записи.
+	Op        string    `json:"op"`            // Op - операция, например MOVE.
+	Src       string    `json:"src"`           // Src - исходный путь.
+	Dst       string    `json:"dst,omitempty"` // Dst - новый путь (для операций с двумя путями).
+	Size      int64     `json:"size"`          // Size - размер файла в байтах.
+	Timestamp time.Time `json:"timestamp"`     // Timestamp - время операции (UTC).
+	User      string    `json:"user"`          // User - логин HTTP Basic (пустой, если он не передан).
+	Status    string    `json:"status"`        // Status - результат: ok или failed с текстом ошибки.
+}
+
+// JournalFilter - условия отбора записей журнала.
+type JournalFilter struct {
+	From time.Time // From - записи не раньше этого времени (нулевое - без ограничения).
+	Op   string    // Op - только записи с этой операцией (пустая - любые).
+}
+
+// match - метод для проверки записи на соответствие условиям.
+func (f JournalFilter) match(entry JournalEntry) bool {
+	if !f.From.IsZero() && entry.Timestamp.Before(f.From) {
+		return false
+	}
+	return f.Op == "" || strings.EqualFold(entry.Op, f.Op)
+}
+
+// Journal - журнал изменений, дописываемый в файл по одной записи JSON на строку. Когда файл
+// превышает maxSize, он переименовывается в file.1 (старые - в file.2 и далее), а запись
+// продолжается в новый файл; хранится не больше keep старых файлов.
+type Journal struct {
+	mu      sync.Mutex
+	file    string // file - путь к текущему файлу журнала.
+	maxSize int64  // maxSize - размер, после которого файл журнала заменяется новым (0 - без ограничения).
+	keep    int    // keep - количество сохраняемых старых файлов.
+}
+
+// journal - журнал изменений сервера, nil если журнал недоступен.
+var journal *Journal
+
+// loadJournal - функция для открытия журнала изменений в домашней директории пользователя.
+func loadJournal(maxSize int64, keep int) (*Journal, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewJournal(filepath.Join(home, ".filesystem", "journal.log"), maxSize, keep)
+}
+
+// NewJournal - функция для создания журнала изменений в файле file.
+func NewJournal(file string, maxSize int64, keep int) (*Journal, error) {
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		return nil, err
+	}
+	if keep < 0 {
+		keep = 0
+	}
+	return &Journal{file: file, maxSize: maxSize, keep: keep}, nil
+}
+
+// Append - метод для добавления записи в журнал. Идентификатор и время заполняются автоматически.
+// Для отключенного журнала (nil) ничего не делает.
+func (j *Journal) Append(entry JournalEntry) error {
+	if j == nil {
+		return nil
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+	entry.ID = hex.EncodeToString(id)
+	entry.Timestamp = time.Now().UTC()
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if err := j.rotateIfNeeded(int64(len(line))); err != nil {
+		return fmt.Errorf("ошибка ротации журнала изменений %s: %w", j.file, err)
+	}
+	file, err := os.OpenFile(j.file, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(line); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// rotateIfNeeded - метод для замены файла журнала новым, если запись n байт превысит maxSize.
+// Вызывается под мьютексом.
+func (j *Journal) rotateIfNeeded(n int64) error {
+	if j.maxSize <= 0 {
+		return nil
+	}
+	info, err := os.Stat(j.file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Size() == 0 || info.Size()+n <= j.maxSize {
+		return nil
+	}
+
+	if j.keep <= 0 {
+		return os.Remove(j.file)
+	}
+	if err := os.Remove(j.rotatedFile(j.keep)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for i := j.keep - 1; i >= 1; i-- {
+		if err := os.Rename(j.rotatedFile(i), j.rotatedFile(i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return os.Rename(j.file, j.rotatedFile(1))
+}
+
+// rotatedFile - метод для получения пути к старому файлу журнала с номером i.
+func (j *Journal) rotatedFile(i int) string {
+	return j.file + "." + strconv.Itoa(i)
+}
+
+// Query - метод для чтения записей журнала, подходящих под filter, от старых к новым.
+// Просматриваются и сохраненные старые файлы. Поврежденные строки (например, недописанные
+// при аварийном завершении) пропускаются.
+func (j *Journal) Query(filter JournalFilter) ([]JournalEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	files := make([]string, 0, j.keep+1)
+	for i := j.keep; i >= 1; i-- {
+		files = append(files, j.rotatedFile(i))
+	}
+	files = append(files, j.file)
+
+	entries := []JournalEntry{}
+	for _, name := range files {
+		file, err := os.Open(name)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+		for scanner.Scan() {
+			var entry JournalEntry
+			if json.Unmarshal(scanner.Bytes(), &entry) != nil {
+				continue
+			}
+			if filter.match(entry) {
+				entries = append(entries, entry)
+			}
+		}
+		err = scanner.Err()
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения журнала изменений %s: %w", name, err)
+		}
+	}
+	return entries, nil
+}
+
+// parseJournalTime - функция для разбора параметра from: дата (2024-01-01) или время в RFC 3339.
+func parseJournalTime(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// handleJournal - функция-обработчик для просмотра журнала изменений. Параметры: from - дата
+// (2024-01-01) или время RFC 3339, начиная с которого выводятся записи; op - операция (MOVE).
+func handleJournal(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeJSONError(w, http.StatusMethodNotAllowed, "метод не поддерживается")
+		return
+	}
+	if journal == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "журнал изменений отключен: не удалось открыть файл журнала")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := JournalFilter{Op: query.Get("op")}
+	if from := query.Get("from"); from != "" {
+		t, err := parseJournalTime(from)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "неправильный параметр from: используйте дату 2024-01-01 или время RFC 3339")
+			return
+		}
+		filter.From = t
+	}
+
+	entries, err := journal.Query(filter)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
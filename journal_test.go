@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJournalRotation(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "journal.log")
+	j, err := NewJournal(file, 300, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		if err := j.Append(JournalEntry{Op: JournalOpMove, Src: "/data/a.txt", Dst: "/data/b.txt", Status: JournalStatusOK}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{file, file + ".1", file + ".2"} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if info.Size() > 300 {
+			t.Errorf("%s: size %d exceeds the limit", name, info.Size())
+		}
+	}
+	// Хранится не больше двух старых файлов.
+	if _, err := os.Stat(file + ".3"); !os.IsNotExist(err) {
+		t.Errorf("%s.3 should have been removed, stat err = %v", file, err)
+	}
+
+	entries, err := j.Query(JournalFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) == 0 || len(entries) >= 10 {
+		t.Fatalf("Query returned %d entries, want some but not all of the 10 after rotation", len(entries))
+	}
+	for i := 1; i < len(entries); i++ {
+		if entries[i].Timestamp.Before(entries[i-1].Timestamp) {
+			t.Fatalf("entries are not in chronological order: %v before %v", entries[i-1].Timestamp, entries[i].Timestamp)
+		}
+	}
+}
+
+func TestJournalQueryFilter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "journal.log")
+	old := []JournalEntry{
+		{ID: "old", Op: "DELETE", Src: "/data/old", Timestamp: time.Date(2023, 12, 31, 23, 0, 0, 0, time.UTC), Status: JournalStatusOK},
+		{ID: "new", Op: "DELETE", Src: "/data/new", Timestamp: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Status: JournalStatusOK},
+	}
+	var buf bytes.Buffer
+	for _, entry := range old {
+		if err := json.NewEncoder(&buf).Encode(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Недописанная строка не мешает чтению остальных записей.
+	buf.WriteString(`{"id":"broken","op":"DEL` + "\n")
+	if err := os.WriteFile(file, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	j, err := NewJournal(file, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Append(JournalEntry{Op: JournalOpMove, Src: "/data/a", Dst: "/data/b", Status: JournalStatusOK}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		filter JournalFilter
+		want   []string
+	}{
+		{JournalFilter{}, []string{"/data/old", "/data/new", "/data/a"}},
+		{JournalFilter{Op: "delete"}, []string{"/data/old", "/data/new"}},
+		{JournalFilter{Op: JournalOpMove}, []string{"/data/a"}},
+		{JournalFilter{From: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Op: "DELETE"}, []string{"/data/new"}},
+	}
+	for _, tt := range tests {
+		entries, err := j.Query(tt.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make([]string, len(entries))
+		for i, entry := range entries {
+			got[i] = entry.Src
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("Query(%+v) = %q, want %q", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestBulkRenameJournal(t *testing.T) {
+	saved, savedJournal := config, journal
+	t.Cleanup(func() { config, journal = saved, savedJournal })
+	config.Logger = NewTextLogger(io.Discard)
+	config.RenameEnabled = true
+
+	var err error
+	journal, err = NewJournal(filepath.Join(t.TempDir(), "journal.log"), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.log"), []byte("12345"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	body := `{"dir":` + strconv.Quote(dir) + `,"pattern":"*.log","template":"{{.Name}}.txt"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/bulk-rename", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth("alice", "secret")
+	rec := httptest.NewRecorder()
+	handleBulkRename(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	handleJournal(rec, httptest.NewRequest(http.MethodGet, "/api/journal?op=MOVE&from=2024-01-01", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("journal status = %d, body %s", rec.Code, rec.Body)
+	}
+	var entries []JournalEntry
+	if err := json.Unmarshal(rec.Body.Bytes(), &entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("journal has %d entries, want 1: %s", len(entries), rec.Body)
+	}
+	entry := entries[0]
+	if entry.Op != JournalOpMove || entry.Src != filepath.Join(dir, "a.log") || entry.Dst != filepath.Join(dir, "a.txt") ||
+		entry.Size != 5 || entry.User != "alice" || entry.Status != JournalStatusOK || entry.ID == "" {
+		t.Errorf("unexpected journal entry %+v", entry)
+	}
+}
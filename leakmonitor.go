@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"time"
+)
+
+// leakProfileAfter - сколько интервалов подряд должен расти объем кучи, чтобы был записан профиль.
+const leakProfileAfter = 3
+
+// memLeakMonitor - функция для фонового поиска утечек памяти. Каждые interval сравнивает объем кучи
+// с предыдущим замером и предупреждает, если рост превышает threshold байт в минуту. Если рост
+// держится дольше leakProfileAfter интервалов подряд, записывает профиль кучи во временный файл.
+// Работает до отмены ctx.
+func memLeakMonitor(ctx context.Context, interval time.Duration, threshold float64, logger Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	prev := stats.HeapAlloc
+	growing := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		runtime.ReadMemStats(&stats)
+		rate := (float64(stats.HeapAlloc) - float64(prev)) / interval.Minutes()
+		prev = stats.HeapAlloc
+
+		if rate <= threshold {
+			growing = 0
+			continue
+		}
+
+		growing++
+		logger.Warn(fmt.Sprintf("Предупреждение: куча растет на %.0f байт/мин (порог %.0f), занято %d байт", rate, threshold, stats.HeapAlloc))
+		if growing <= leakProfileAfter {
+			continue
+		}
+
+		path, err := writeHeapProfile()
+		if err != nil {
+			logger.Error("Ошибка при записи профиля кучи:", err)
+		} else {
+			logger.Warn(fmt.Sprintf("Рост кучи держится %d интервалов подряд, профиль записан в %s", growing, path))
+		}
+		growing = 0
+	}
+}
+
+// writeHeapProfile - функция для записи профиля кучи во временный файл. Возвращает путь к файлу.
+func writeHeapProfile() (string, error) {
+	file, err := os.CreateTemp("", "filesystem-heap-*.pprof")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if err := pprof.WriteHeapProfile(file); err != nil {
+		return "", err
+	}
+	return file.Name(), nil
+}
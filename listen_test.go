@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+)
+
+func TestListenIPv6(t *testing.T) {
+	probe, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 loopback unavailable: %v", err)
+	}
+	_ = probe.Close()
+
+	var cfg Config
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	defineFlags(fs, &cfg)
+	if err := fs.Parse([]string{"--bind-network", "tcp6", "--addr", "[::1]:0"}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+
+	listener, err := net.Listen(cfg.BindNetwork, cfg.listenAddr())
+	if err != nil {
+		t.Fatalf("listen %s %s: %v", cfg.BindNetwork, cfg.listenAddr(), err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(handleHealthz)}
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(func() { _ = server.Close() })
+
+	if ip := listener.Addr().(*net.TCPAddr).IP; !ip.Equal(net.IPv6loopback) {
+		t.Fatalf("listener address = %v, want ::1", ip)
+	}
+
+	resp, err := http.Get("http://" + listener.Addr().String() + "/healthz")
+	if err != nil {
+		t.Fatalf("GET over IPv6: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, body %s", resp.StatusCode, body)
+	}
+}
+
+func TestBindNetworkRejectsUnknown(t *testing.T) {
+	var cfg Config
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	defineFlags(fs, &cfg)
+	if err := fs.Parse([]string{"--bind-network", "udp"}); err == nil {
+		t.Fatal("--bind-network udp accepted")
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Logger - интерфейс журнала сервера. Аргументы объединяются так же, как в log.Println.
+type Logger interface {
+	Info(args ...interface{})  // Info - запись о штатном событии.
+	Warn(args ...interface{})  // Warn - предупреждение о ситуации, требующей внимания.
+	Error(args ...interface{}) // Error - запись об ошибке.
+	Fatal(args ...interface{}) // Fatal - запись об ошибке с завершением процесса.
+
+	WithRequestID(id string) Logger // WithRequestID - журнал, добавляющий идентификатор запроса к каждой записи.
+}
+
+// Форматы журнала для --log-format.
+const (
+	logFormatText = "text" // logFormatText - строки как у стандартного пакета log.
+	logFormatJSON = "json" // logFormatJSON - по одному JSON-объекту на строку.
+)
+
+// newLogger - функция для создания журнала в формате format.
+func newLogger(format string, out io.Writer) Logger {
+	if format == logFormatJSON {
+		return NewJSONLogger(out)
+	}
+	return NewTextLogger(out)
+}
+
+// logMessage - функция для объединения аргументов в строку по правилам log.Println.
+func logMessage(args []interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+}
+
+// textLogger - журнал в прежнем текстовом формате стандартного пакета log.
+type textLogger struct {
+	std *log.Logger
+}
+
+// NewTextLogger - функция для создания текстового журнала, совместимого с прежним выводом.
+func NewTextLogger(out io.Writer) Logger {
+	return &textLogger{std: log.New(out, "", log.LstdFlags)}
+}
+
+// Info - метод для записи штатного события.
+func (l *textLogger) Info(args ...interface{}) {
+	_ = l.std.Output(2, logMessage(args))
+}
+
+// Warn - метод для записи предупреждения.
+func (l *textLogger) Warn(args ...interface{}) {
+	_ = l.std.Output(2, logMessage(args))
+}
+
+// Error - метод для записи ошибки.
+func (l *textLogger) Error(args ...interface{}) {
+	_ = l.std.Output(2, logMessage(args))
+}
+
+// Fatal - метод для записи ошибки и завершения процесса.
+func (l *textLogger) Fatal(args ...interface{}) {
+	_ = l.std.Output(2, logMessage(args))
+	os.Exit(1)
+}
+
+// WithRequestID - метод для получения журнала с идентификатором запроса после даты.
+func (l *textLogger) WithRequestID(id string) Logger {
+	if id == "" {
+		return l
+	}
+	return &textLogger{std: log.New(l.std.Writer(), "["+id+"] ", l.std.Flags()|log.Lmsgprefix)}
+}
+
+// jsonLogger - журнал в формате JSON Lines для систем сбора логов.
+type jsonLogger struct {
+	mu        *sync.Mutex // mu - общая для производных журналов блокировка, чтобы строки не перемешивались.
+	out       io.Writer
+	requestID string
+}
+
+// jsonLogEntry - структура одной записи JSON-журнала.
+type jsonLogEntry struct {
+	Time      string `json:"time"`                // Time - время записи в RFC 3339.
+	Level     string `json:"level"`               // Level - уровень: info, warn, error или fatal.
+	Msg       string `json:"msg"`                 // Msg - текст записи.
+	RequestID string `json:"requestId,omitempty"` // RequestID - идентификатор запроса, если запись к нему относится.
+}
+
+// NewJSONLogger - функция для создания журнала, пишущего по одному JSON-объекту на строку.
+func NewJSONLogger(out io.Writer) Logger {
+	return &jsonLogger{mu: &sync.Mutex{}, out: out}
+}
+
+// write - метод для записи одной строки журнала.
+func (l *jsonLogger) write(level string, args []interface{}) {
+	line, err := json.Marshal(jsonLogEntry{
+		Time:      time.Now().Format(time.RFC3339Nano),
+		Level:     level,
+		Msg:       logMessage(args),
+		RequestID: l.requestID,
+	})
+	if err != nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = l.out.Write(append(line, '\n'))
+}
+
+// Info - метод для записи штатного события.
+func (l *jsonLogger) Info(args ...interface{}) {
+	l.write("info", args)
+}
+
+// Warn - метод для записи предупреждения.
+func (l *jsonLogger) Warn(args ...interface{}) {
+	l.write("warn", args)
+}
+
+// Error - метод для записи ошибки.
+func (l *jsonLogger) Error(args ...interface{}) {
+	l.write("error", args)
+}
+
+// Fatal - метод для записи ошибки и завершения процесса.
+func (l *jsonLogger) Fatal(args ...interface{}) {
+	l.write("fatal", args)
+	os.Exit(1)
+}
+
+// WithRequestID - метод для получения журнала, добавляющего requestId к записям.
+func (l *jsonLogger) WithRequestID(id string) Logger {
+	return &jsonLogger{mu: l.mu, out: l.out, requestID: id}
+}
+
+// loggerWriter - адаптер io.Writer для перенаправления стандартного пакета log (в том числе
+// сообщений net/http) в журнал сервера как ошибок.
+type loggerWriter struct {
+	logger Logger
+}
+
+// Write - метод для записи строки стандартного журнала.
+func (w loggerWriter) Write(p []byte) (int, error) {
+	w.logger.Error(strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
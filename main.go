@@ -1,41 +1,152 @@
 package main
 
 import (
-	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
-	"log"
+	"io/fs"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
 	"time"
 
 	filesystem "filesystem/file_system"
 
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 // PageData - структура для передачи данных в шаблон.
 type PageData struct {
 	FileList []filesystem.FileInfo // FileList - список файлов и директорий.
-	EndTime  string                // EndTime - время выполнения программы.
+	EndTime  string                // EndTime - время выполнения программы в удобном для чтения виде.
+	EndRaw   string                // EndRaw - время выполнения программы в формате time.Duration.
 	ErrorMsg string                // ErrorMsg - поле для вывода ошибки при неправильно введенной директории.
 	LastPath string                // LastPath - поле для вывода последнего введенного пути.
+	LastSort string                // LastSort - поле для вывода последнего выбранного типа сортировки.
+	LastBy   string                // LastBy - поле для вывода последнего выбранного ключа сортировки.
+
+	LastDirsFirst bool                  // LastDirsFirst - директории выводились перед файлами.
+	LastDepth     string                // LastDepth - глубина вывода из запроса (пустая - по --list-depth).
+	Refresh       bool                  // Refresh - признак повторного сканирования с выводом изменений.
+	Added         []string              // Added - имена появившихся с прошлого сканирования элементов.
+	Removed       []string              // Removed - имена исчезнувших с прошлого сканирования элементов.
+	Grown         []filesystem.FileInfo // Grown - элементы, размер которых увеличился.
+	Shrunk        []filesystem.FileInfo // Shrunk - элементы, размер которых уменьшился.
+	GitBlame      bool                  // GitBlame - признак вывода сведений о последних коммитах.
+	Lang          string                // Lang - язык интерфейса.
+	Messages      map[string]string     // Messages - каталог сообщений интерфейса.
+
+	Bookmarks []Bookmark // Bookmarks - закладки для боковой панели навигации.
+
+	Dashboard *DashboardData // Dashboard - статистика сервера для главной страницы.
+
+	Columns []Column // Columns - заголовки столбцов таблицы со ссылками на сортировку.
+
+	Breadcrumbs []BreadcrumbItem // Breadcrumbs - цепочка родительских директорий для перехода вверх.
+	ParentPath  string           // ParentPath - родительская директория (пустая в корне файловой системы).
+
+	Banner template.HTML // Banner - объявление администратора над страницей.
+
+	Errors []string // Errors - предупреждения о директориях, которые не удалось прочитать.
+
+	SkippedMounts []string // SkippedMounts - сетевые точки монтирования, пропущенные при сканировании.
+
+	SkippedCount int     // SkippedCount - количество элементов, скрытых правилами исключения.
+	SkippedBytes float64 // SkippedBytes - суммарный размер скрытых файлов.
+	SkippedSize  string  // SkippedSize - размер скрытых файлов для вывода, с единицей измерения.
+
+	Degraded       bool   // Degraded - ответ сформирован в режиме деградации из кэша.
+	DegradedReason string // DegradedReason - причина перехода в режим деградации.
+
+	Truncated    bool    // Truncated - подсчет размера остановлен по лимиту --max-scan-size.
+	ScannedBytes float64 // ScannedBytes - размер, подсчитанный до остановки.
 }
 
+// scanCacheSize - количество директорий, результаты сканирования которых хранятся в кэше.
+const scanCacheSize = 128
+
+// scanCache - кэш предыдущих результатов сканирования для режима повторного сканирования.
+var scanCache = filesystem.NewScanCache(scanCacheSize)
+
 func main() {
-	// Загружаем переменные окружения из .env файла
-	err := godotenv.Load()
+	config = loadConfig()
+
+	// Загружаем каталоги сообщений и проверяем язык сервера.
+	err := loadCatalogs()
+	if err != nil {
+		config.Logger.Fatal(err)
+	}
+	if _, ok := catalogs[config.Lang]; !ok {
+		config.Logger.Fatal(fmt.Sprintf("язык %q не поддерживается", config.Lang))
+	}
+
+	// В режиме просмотра читаем результаты сканирования из файла.
+	if config.CacheFile != "" {
+		cachedDirs, err = loadCacheFile(config.CacheFile)
+		if err != nil {
+			config.Logger.Fatal(err)
+		}
+	}
+
+	// Определяем сетевые точки монтирования, которые не нужно сканировать.
+	if config.SkipNetworkMounts {
+		networkMounts, err = filesystem.NetworkMounts()
+		if err != nil {
+			config.Logger.Fatal(err)
+		}
+	}
+
+	// Загружаем закладки пользователя. Без них сервер продолжает работать, только закладки отключаются.
+	bookmarks, err = loadBookmarks()
+	if err != nil {
+		config.Logger.Warn("Предупреждение: закладки отключены:", err)
+	}
+
+	// Открываем журнал изменений. Без него изменения выполняются, но не записываются.
+	journal, err = loadJournal(config.JournalMaxSize, config.JournalKeep)
 	if err != nil {
-		log.Fatal("Ошибка загрузки .env файла")
+		config.Logger.Warn("Предупреждение: журнал изменений отключен:", err)
+	}
+
+	// Загружаем переменные окружения из .env файла. Без файла настройки берутся из окружения,
+	// чтобы собранный сервер можно было запускать из любой директории.
+	err = godotenv.Load()
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		config.Logger.Fatal("Ошибка загрузки .env файла")
+	}
+
+	addr := config.listenAddr()
+
+	// Следим за памятью, чтобы кэш сканирования не разрастался бесконтрольно.
+	guardCtx, stopGuard := context.WithCancel(context.Background())
+	defer stopGuard()
+	go memoryGuard(guardCtx, config.MaxCacheMemory)
+	if len(config.WatchSizes) > 0 {
+		startWatches(guardCtx, config.WatchSizes)
+	}
+	banner.Set(config.Banner)
+	go watchBannerReload(guardCtx)
+	if config.LeakMonitorInterval > 0 {
+		go memLeakMonitor(guardCtx, config.LeakMonitorInterval, config.LeakMonitorThreshold, config.Logger)
 	}
 
-	port := os.Getenv("SERVER_PORT")
+	server := startHTTPServer(addr)
 
-	server := startHTTPServer(port)
-	fmt.Printf("Для запуска приложения введите в адресную строку localhost%s\n", port)
+	// Записываем PID для систем управления процессами.
+	if config.PidFile != "" {
+		if err := writePidFile(config.PidFile, config.PidFileOverwrite); err != nil {
+			config.Logger.Fatal(err)
+		}
+	}
+	fmt.Printf("Для запуска приложения введите в адресную строку localhost%s\n", addr)
 	waitForShutdownSignal(server)
 }
 
@@ -43,17 +154,148 @@ func main() {
 func startHTTPServer(addr string) *http.Server {
 	server := &http.Server{Addr: addr}
 
-	fs := http.FileServer(http.Dir("web/static"))
-	http.Handle("/web/static/", http.StripPrefix("/web/static/", fs))
+	http.Handle("/web/static/", http.StripPrefix("/web/static/", staticHandler()))
+
+	// Прогреваем кэш в фоне, сервер при этом сразу принимает соединения.
+	if len(config.PrewarmPaths) > 0 {
+		startPrewarm(config.PrewarmPaths)
+	}
+
+	// Контролируем время ответа, при частых нарушениях переходим на выдачу из кэша.
+	if config.SLOMaxMs > 0 {
+		slo = NewSLOMonitor(time.Duration(config.SLOMaxMs)*time.Millisecond, config.SLOMaxRate)
+	}
+
+	// Регистрируем обработчики. Для тех, что сканируют файловую систему, ограничиваем частоту запросов
+	// (ограничитель общий) и время сканирования: контекст с крайним сроком прерывает обход директорий.
+	var limiter, timeout func(http.Handler) http.Handler
+	if config.RateLimit > 0 {
+		limiter = rateLimitMiddleware(NewRateLimiter(config.RateLimit, config.RateBurst))
+	}
+	if config.RequestTimeout > 0 {
+		timeout = timeoutMiddleware(config.RequestTimeout)
+	}
+	scanning := func(handler http.HandlerFunc) http.Handler {
+		var wrapped http.Handler = handler
+		if limiter != nil {
+			wrapped = limiter(wrapped)
+		}
+		if timeout != nil {
+			wrapped = timeout(wrapped)
+		}
+		return wrapped
+	}
+	// Кэш ответов подключается поверх ограничителя, поэтому ответы из кэша не расходуют жетоны.
+	rootHandler := scanning(handleRoot)
+	if config.ResponseCacheTTL > 0 {
+		responseCache = NewResponseCache(config.ResponseCacheTTL, config.ResponseCacheMaxBytes, config.ResponseCacheTotalBytes)
+		rootHandler = responseCacheMiddleware(responseCache)(rootHandler)
+	}
+	http.Handle("/", rootHandler)
+	http.Handle("/api/files", scanning(handleFiles))
+	http.Handle("/export/csv", scanning(handleExportCSV))
+	http.Handle("/api/tree", scanning(handleTree))
+	http.Handle("/api/tree/text", scanning(handleTreeText))
+	http.Handle("/api/search", scanning(handleSearch))
+	http.Handle("/api/duplicates", scanning(handleDuplicates))
+	http.Handle("/api/top", scanning(handleTop))
+	http.Handle("/api/by-extension", scanning(handleByExtension))
+	http.Handle("/api/diff", scanning(handleCompareDirs))
+	http.HandleFunc("/api/size/stream", handleSizeStream)
+	http.HandleFunc("/api/bookmarks", handleBookmarks)
+	http.HandleFunc("/api/bookmarks/", handleBookmark)
+	http.HandleFunc("/api/executables", handleExecutables)
+	http.HandleFunc("/api/compression-estimate", handleCompressionEstimate)
+	http.HandleFunc("/api/verify", handleVerify)
+	http.HandleFunc("/api/watches", handleWatches)
+	http.Handle("/api/bulk-rename", adminHandler(handleBulkRename))
+	http.Handle("/api/journal", adminHandler(handleJournal))
+	http.HandleFunc("/health/live", handleHealthLive)
+	http.HandleFunc("/healthz", handleHealthz)
+	http.HandleFunc("/readyz", handleReadyz)
+	http.HandleFunc("/api/deeplink", handleDeepLink)
+	http.HandleFunc("/download", handleDownload)
+	http.HandleFunc("/download-zip", handleDownloadZip)
+	http.HandleFunc("/preview", handlePreview)
+	http.HandleFunc("/view/", handleView)
+	http.Handle("/api/config", adminHandler(handleConfig))
+	http.Handle("/api/debug/gc", adminHandler(handleDebugGC))
+	http.Handle("/api/admin/reset-degraded", adminHandler(handleResetDegraded))
+	// Метрики выводим на отдельном адресе, если он задан, чтобы не открывать их вместе с сервером.
+	if config.MetricsAddr != "" {
+		startMetricsServer(config.MetricsAddr)
+	} else {
+		http.Handle("/metrics", promhttp.Handler())
+	}
+
+	// При заданном сертификате или --tls-auto принимаем соединения по HTTPS.
+	useTLS, err := config.tlsEnabled()
+	if err != nil {
+		config.Logger.Fatal(err)
+	}
+
+	// Оборачиваем обработчики в middleware изнутри наружу, журнал доступа - самый внешний слой.
+	var handler http.Handler = http.DefaultServeMux
+	if config.APIKey != "" {
+		handler = apiKeyMiddleware(config.APIKey)(handler)
+	}
+	if config.AuthUser != "" && config.AuthPassword != "" {
+		handler = basicAuthMiddleware(config.AuthUser, config.AuthPassword)(handler)
+	} else if config.AuthUser != "" || config.AuthPassword != "" {
+		config.Logger.Fatal("флаги --auth-user и --auth-password задаются только вместе")
+	}
+	handler = requestIDMiddleware(handler)
+	if !config.NoGzip {
+		handler = gzipMiddleware(handler)
+	}
+	if len(config.CORSOrigins) > 0 {
+		handler = corsMiddleware(config.CORSOrigins, config.CORSMethods)(handler)
+	}
+	// Перехват паники оборачивает все, кроме заголовков безопасности, метрик и журнала доступа:
+	// паника в любом middleware внутри превращается в ответ 500, который видят метрики и журнал.
+	handler = recoveryMiddleware(handler)
+	handler = securityHeadersMiddleware(config.SecurityHeaders, useTLS)(handler)
+	handler = metricsMiddleware(handler)
+
+	accessLog, err := openAccessLog(config.AccessLog)
+	if err != nil {
+		config.Logger.Fatal(err)
+	}
+	server.Handler = accessLogMiddleware(accessLog)(handler)
+
+	// Открываем сокет в выбранной сети: tcp - IPv4 и IPv6, tcp4 или tcp6 - только одна из них.
+	listener, err := net.Listen(config.BindNetwork, addr)
+	if err != nil {
+		config.Logger.Fatal(fmt.Sprintf("Ошибка при запуске сервера: %v", err))
+	}
 
-	// Регистрируем обработчики.
-	http.HandleFunc("/", handleFileSystem)
+	if useTLS && config.TLSCert == "" {
+		cert, err := selfSignedCertificate()
+		if err != nil {
+			config.Logger.Fatal(fmt.Sprintf("Ошибка при создании самоподписанного сертификата: %v", err))
+		}
+		server.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+		config.Logger.Info("Используется самоподписанный сертификат, браузер покажет предупреждение")
+	}
+
+	// Перенаправляем HTTP на HTTPS, если задан порт для перенаправления.
+	if config.HTTPRedirectPort > 0 {
+		if !useTLS {
+			config.Logger.Fatal("флаг --http-redirect-port задается вместе с --tls-cert и --tls-key или --tls-auto")
+		}
+		_, httpsPort, _ := net.SplitHostPort(listener.Addr().String())
+		startHTTPRedirectServer(config.HTTPRedirectPort, httpsPort)
+	}
 
 	// Запускаем сервер в отдельной горутине.
 	go func() {
-		log.Println("Сервер запущен на", addr)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Ошибка при запуске сервера: %v", err)
+		config.Logger.Info("Сервер запущен на", listener.Addr())
+		serve := func() error { return server.Serve(listener) }
+		if useTLS {
+			serve = func() error { return server.ServeTLS(listener, config.TLSCert, config.TLSKey) }
+		}
+		if err := serve(); err != nil && err != http.ErrServerClosed {
+			config.Logger.Fatal(fmt.Sprintf("Ошибка при запуске сервера: %v", err))
 		}
 	}()
 
@@ -69,65 +311,270 @@ func waitForShutdownSignal(server *http.Server) {
 	// Ожидаем завершения контекста (сигнала os.Interrupt)
 	<-ctx.Done()
 
-	log.Println("Получен сигнал для остановки сервера...")
+	config.Logger.Info("Получен сигнал для остановки сервера...")
 
 	// Создаем контекст с таймаутом для graceful shutdown
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
 	defer cancel()
 
 	// Пытаемся корректно завершить работу сервера
 	if err := server.Shutdown(shutdownCtx); err != nil {
-		log.Fatalf("Ошибка при завершении работы сервера: %v", err)
+		config.Logger.Fatal(fmt.Sprintf("Ошибка при завершении работы сервера: %v", err))
+	}
+	if config.PidFile != "" {
+		defer removePidFile(config.PidFile)
+	}
+
+	config.Logger.Info("Сервер корректно завершил работу.")
+}
+
+// handleRoot - функция-обработчик корня: без параметра root перенаправляет в директорию по умолчанию,
+// а если она не задана, выводит главную страницу со статистикой.
+func handleRoot(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("root") == "" {
+		if config.DefaultRoot != "" {
+			query := url.Values{}
+			query.Set("root", config.DefaultRoot)
+			query.Set("sort", "asc")
+			http.Redirect(w, r, "/?"+query.Encode(), http.StatusFound)
+			return
+		}
+		handleDashboard(w, r)
+		return
 	}
+	handleFileSystem(w, r)
+}
+
+// handleDashboard - функция-обработчик главной страницы со статистикой сервера.
+func handleDashboard(w http.ResponseWriter, r *http.Request) {
+	dashboard := stats.Dashboard()
 
-	log.Println("Сервер корректно завершил работу.")
+	// Статистика меняется с каждым запросом, поэтому страницу не кэшируем.
+	w.Header().Set("Cache-Control", "no-store")
+	renderTemplate(w, PageData{Dashboard: &dashboard, Lang: resolveLang(r)})
 }
 
 // handleFileSystem - функция-обработчик для работы с файловой системой.
 func handleFileSystem(w http.ResponseWriter, r *http.Request) {
 	startTime := time.Now()
+	logger := requestLogger(r)
+	defer func() { slo.Observe(r.URL.Query().Get("root"), time.Since(startTime)) }()
+	lang := resolveLang(r)
+	format := negotiateFormat(r)
+	w.Header().Add("Vary", "Accept")
 
 	// Проверяем, есть ли параметры в запросе.
-	dirPath, sortType, err := parseFlags(r)
+	dirPath, sortOpts, err := parseFlags(r, lang)
 	if err != nil {
+		status := http.StatusBadRequest
+		var forbidden *forbiddenPathError
+		if errors.As(err, &forbidden) {
+			status = http.StatusForbidden
+		}
+		if format == formatJSON {
+			writeJSONError(w, status, err.Error())
+			return
+		}
+		if status == http.StatusForbidden {
+			http.Error(w, err.Error(), status)
+			return
+		}
 		// Если параметры не указаны, просто отображаем форму.
 		if dirPath == "" {
-			renderTemplate(w, PageData{})
+			renderTemplate(w, PageData{Lang: lang})
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	envelope, err := parseEnvelope(r)
+	if err != nil && format == formatJSON {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	scanOpts := config.requestScanOptions(r)
+	if scanOpts.ListDepth, err = parseDepth(r, lang); err != nil {
+		if format == formatJSON {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	binary, err := parseBinary(r, lang)
+	if err != nil {
+		if format == formatJSON {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
 			return
 		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	// Кэш сканирования хранит списки только для глубины по умолчанию.
+	cacheable := scanOpts.ListDepth == config.ListDepth
+
+	// Пока директория прогревается, сообщаем о ходе прогрева.
+	if status, ok := checkPrewarm(dirPath); !ok {
+		writePrewarmStatus(w, status)
+		return
+	}
+
+	// В потоковом режиме строки выводятся по мере сканирования.
+	if r.URL.Query().Get("mode") == "streaming" && config.CacheFile == "" && format == formatHTML {
+		handleFileSystemStream(w, r, dirPath, scanOpts, binary, lang)
+		return
+	}
+
+	refresh := r.URL.Query().Get("refresh") == "1"
+
+	// Собираем информацию о файлах и директориях: из файла с результатами сканирования, из кэша
+	// прогретых директорий либо с диска.
+	var fileList []filesystem.FileInfo
+	var totalSize float64
+	var skipped filesystem.SkipStats
+	var prewarmed bool
+	degraded := slo.Degraded()
+	if cacheable && !refresh && config.CacheFile == "" {
+		fileList, prewarmed = prewarmedList(dirPath)
+	}
+	if prewarmed {
+		for _, fileInfo := range fileList {
+			totalSize += fileInfo.Size
+		}
+	} else if config.CacheFile != "" {
+		fileList, totalSize, err = listDirFromCache(dirPath)
+	} else if degraded {
+		// В режиме деградации не сканируем, а отдаем только сохраненные результаты.
+		var ok bool
+		if cacheable {
+			fileList, ok = scanCache.Get(dirPath)
+		}
+		if !ok {
+			writeDegradedUnavailable(w, format, lang)
+			return
+		}
+		for _, fileInfo := range fileList {
+			totalSize += fileInfo.Size
+		}
+	} else {
+		fileList, skipped, err = filesystem.ListDirWithSkipped(r.Context(), dirPath, scanOpts)
+	}
 
-	// Собираем информацию о файлах и директориях.
-	fileList, err := filesystem.ListDirByReadDir(dirPath)
+	// При частичном сканировании выводим прочитанное, а ошибки показываем как предупреждения.
+	var warnings []string
+	var partial *filesystem.PartialError
+	if errors.As(err, &partial) {
+		for _, readErr := range partial.Errors {
+			warnings = append(warnings, fmt.Sprintf(translate(lang, "warning.dir_read"), readErr))
+		}
+		err = nil
+	}
+	// Клиент отключился или истекло время запроса - ответ отправит timeoutMiddleware, если он нужен.
+	if ctxErr := r.Context().Err(); ctxErr != nil {
+		logger.Info("Сканирование прервано:", dirPath, ctxErr)
+		return
+	}
 	if err != nil {
 		// Заполняем сообщение об ошибке.
 		data := PageData{
 			FileList: nil,
-			EndTime:  time.Since(startTime).String(),
-			ErrorMsg: fmt.Sprintf("Ошибка чтения директории: %v", err),
+			EndTime:  formatElapsed(time.Since(startTime)),
+			ErrorMsg: fmt.Sprintf(translate(lang, "error.dir_read"), err),
+			Lang:     lang,
+		}
+		if format == formatJSON {
+			writeJSONError(w, http.StatusBadRequest, data.ErrorMsg)
+			return
 		}
 		renderTemplate(w, data)
 		return
 	}
 
-	// Сортируем список и переводим в кб/мб/гб
-	filesystem.SortFileList(fileList, sortType)
-	for i := range fileList {
-		fileList[i].Size, fileList[i].Unit = filesystem.ConvertSize(fileList[i].Size)
+	stats.RecordScan(dirPath)
+
+	// Добавляем сведения о последних коммитах, если директория в git-репозитории.
+	if config.GitBlame {
+		if err := filesystem.AddGitInfo(dirPath, fileList); err != nil {
+			logger.Error("Ошибка получения сведений из git:", err)
+		}
 	}
 
-	totalSize := filesystem.GetDirSize(dirPath)
-	endTime := time.Since(startTime).String()
-	statTime := time.Since(startTime).Seconds()
+	// Сортируем список.
+	filesystem.SortFileList(fileList, sortOpts)
+
+	// При повторном сканировании сравниваем результат с предыдущим, сохраненным в кэше.
+	var diff filesystem.ScanDiff
+	if refresh && cacheable {
+		if prevList, ok := scanCache.Get(dirPath); ok {
+			diff = filesystem.DiffFileLists(prevList, fileList)
+		}
+	}
+	if cacheable && !prewarmed {
+		scanCache.Put(dirPath, fileList)
+	}
+
+	// Результат повторного сканирования всегда должен быть свежим.
+	if refresh {
+		w.Header().Set("Cache-Control", "no-store")
+	}
+
+	setPreviewURLs(fileList)
+
+	// Переводим размеры в кб/мб/гб.
+	convertFileListSizes(fileList, binary, lang)
+	convertFileListSizes(diff.Grown, binary, lang)
+	convertFileListSizes(diff.Shrunk, binary, lang)
+
+	// При превышении лимита размера выводим частичный результат с пометкой. Для прогретой директории
+	// оставляем размер, сложенный из сохраненного списка, иначе прогрев не избавил бы от обхода дерева.
+	var truncated bool
+	if config.CacheFile == "" && !degraded && !prewarmed {
+		totalSize, err = filesystem.GetDirSizeLimited(r.Context(), dirPath, config.requestScanOptions(r))
+		truncated = errors.Is(err, filesystem.ErrSizeLimitExceeded)
+		if ctxErr := r.Context().Err(); ctxErr != nil {
+			logger.Info("Подсчет размера прерван:", dirPath, ctxErr)
+			return
+		}
+	}
+	if truncated {
+		warnings = append(warnings, fmt.Sprintf(translate(lang, "warning.truncated"), formatSize(totalSize, binary, lang)))
+	}
+	elapsed := time.Since(startTime)
+	statTime := elapsed.Seconds()
 
 	// Создаем структуру данных для шаблона.
 	data := PageData{
 		FileList: fileList,
-		EndTime:  endTime,
+		EndTime:  formatElapsed(elapsed),
+		EndRaw:   elapsed.String(),
 		ErrorMsg: "",
 		LastPath: dirPath,
+		LastSort: sortOpts.Order,
+		LastBy:   sortOpts.Key,
+
+		LastDirsFirst: sortOpts.DirsFirst,
+		LastDepth:     r.URL.Query().Get("depth"),
+		Refresh:       refresh,
+		Added:         diff.Added,
+		Removed:       diff.Removed,
+		Grown:         diff.Grown,
+		Shrunk:        diff.Shrunk,
+		GitBlame:      config.GitBlame,
+		Lang:          lang,
+		Errors:        warnings,
+
+		SkippedMounts: networkMounts.Under(dirPath),
+
+		SkippedCount: skipped.Count,
+		SkippedBytes: skipped.Bytes,
+		SkippedSize:  formatSize(skipped.Bytes, binary, lang),
+
+		Truncated:    truncated,
+		ScannedBytes: totalSize,
+	}
+	if degraded {
+		data.Degraded = true
+		data.DegradedReason = degradedReasonSLO
 	}
 
 	statURL := os.Getenv("STAT_URL")
@@ -138,47 +585,153 @@ func handleFileSystem(w http.ResponseWriter, r *http.Request) {
 	}
 	jsonData, err := json.Marshal(statData)
 	if err != nil {
-		log.Println("Ошибка при кодировании данных в JSON:", err)
+		logger.Error("Ошибка при кодировании данных в JSON:", err)
 		return
 	}
-	_, err = http.Post(statURL, "application/json", bytes.NewBuffer(jsonData))
-	log.Printf("Отправляем данные: %+v\n", statData)
-	if err != nil {
-		log.Println("Ошибка при отправке данных на сервер:", err)
+	logger.Info(fmt.Sprintf("Отправляем данные: %+v", statData))
+	sendStat(statURL, jsonData)
+
+	// Отправляем ответ в формате HTML или JSON.
+	writePage(w, format, envelope, data)
+}
+
+// convertFileListSizes - вспомогательная функция для перевода размеров списка в кб/мб/гб (при binary - в КиБ/МиБ/ГиБ).
+func convertFileListSizes(fileList []filesystem.FileInfo, binary bool, lang string) {
+	for i := range fileList {
+		var unit string
+		fileList[i].Size, unit = filesystem.ConvertSize(fileList[i].Size, binary)
+		fileList[i].Unit = translate(lang, unit)
 	}
+}
 
-	// Отправляем ответ в формате HTML.
-	renderTemplate(w, data)
+// formatElapsed - функция для вывода времени выполнения в удобном для чтения виде:
+// "< 1ms", "250ms", "3.0 s" или "2m 5s".
+func formatElapsed(d time.Duration) string {
+	switch {
+	case d < time.Millisecond:
+		return "< 1ms"
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%.1f s", d.Seconds())
+	default:
+		return fmt.Sprintf("%dm %ds", int(d.Minutes()), int(d.Seconds())%60)
+	}
 }
 
+// formatSize - вспомогательная функция для вывода размера в байтах в кб/мб/гб на языке lang.
+func formatSize(size float64, binary bool, lang string) string {
+	value, unit := filesystem.ConvertSize(size, binary)
+	return fmt.Sprintf("%v %s", value, translate(lang, unit))
+}
+
+// pushedAssets - статические файлы, отправляемые вместе со страницей по HTTP/2.
+var pushedAssets = []string{"/web/static/style.css", "/web/static/bundle.js"}
+
 // renderTemplate - вспомогательная функция для рендеринга HTML-шаблона.
 func renderTemplate(w http.ResponseWriter, data PageData) {
-	templateFile := "web/templates/index.html"
-	tmpl, err := template.ParseFiles(templateFile)
+	tmpl, err := indexTemplate.Get()
 	if err != nil {
 		http.Error(w, fmt.Sprintf("ошибка загрузки шаблона: %v", err), http.StatusInternalServerError)
 		return
 	}
 
+	if data.Lang == "" {
+		data.Lang = config.Lang
+	}
+	data.Messages = catalogs[data.Lang]
+	data.Columns = buildColumns(data.LastPath, filesystem.SortOptions{
+		Key:       data.LastBy,
+		Order:     data.LastSort,
+		DirsFirst: data.LastDirsFirst,
+	}, data.LastDepth, data.Lang)
+	data.Bookmarks = bookmarks.List()
+	data.Breadcrumbs = buildBreadcrumbs(data.LastPath)
+	data.ParentPath = parentPath(data.LastPath)
+	data.Banner = renderBanner(banner.Get())
+
 	w.Header().Set("Content-Type", "text/html")
+
+	// По HTTP/2 заранее отправляем статические файлы, которые понадобятся странице.
+	if pusher, ok := w.(http.Pusher); ok {
+		for _, asset := range pushedAssets {
+			if err := pusher.Push(asset, nil); err != nil && err != http.ErrNotSupported {
+				config.Logger.Error(fmt.Sprintf("Ошибка при отправке %s: %v", asset, err))
+			}
+		}
+	}
+
 	if err := tmpl.Execute(w, data); err != nil {
 		http.Error(w, fmt.Sprintf("ошибка при рендеринге шаблона: %v", err), http.StatusInternalServerError)
 	}
 }
 
-// parseFlags - функция для обработки флагов и их проверки.
-func parseFlags(r *http.Request) (string, string, error) {
+// parseFlags - функция для обработки флагов и их проверки. Ошибки возвращаются на языке lang.
+// Возвращает путь и параметры сортировки: направление (sort), ключ (by, по умолчанию size)
+// и признак вывода директорий перед файлами (dirsFirst). Для путей, закрытых --forbidden
+// или --root-jail, возвращается *forbiddenPathError.
+func parseFlags(r *http.Request, lang string) (string, filesystem.SortOptions, error) {
 	// Получаем параметры.
-	dirPath := r.URL.Query().Get("root")
-	sortType := r.URL.Query().Get("sort")
+	query := r.URL.Query()
+	dirPath := query.Get("root")
+	sortOpts := filesystem.SortOptions{
+		Key:    query.Get("by"),
+		Order:  query.Get("sort"),
+		Locale: config.SortLocale,
+	}
 
 	if dirPath == "" {
-		return "", "", fmt.Errorf("не указана директория(root)")
+		return "", sortOpts, errors.New(translate(lang, "error.no_root"))
+	}
+	dirPath = filepath.Clean(dirPath)
+	if err := checkPath(dirPath, lang); err != nil {
+		return "", sortOpts, err
+	}
+
+	if sortOpts.Order != "asc" && sortOpts.Order != "desc" {
+		return "", sortOpts, errors.New(translate(lang, "error.bad_sort"))
 	}
 
-	if sortType != "asc" && sortType != "desc" {
-		return "", "", fmt.Errorf("неправильно указан тип сортировки. Используйте 'asc' или 'desc'")
+	switch sortOpts.Key {
+	case "":
+		sortOpts.Key = filesystem.SortBySize
+	case filesystem.SortBySize, filesystem.SortByName, filesystem.SortByMtime, filesystem.SortByExt:
+	default:
+		return "", sortOpts, errors.New(translate(lang, "error.bad_sort_key"))
 	}
 
-	return dirPath, sortType, nil
+	switch query.Get("dirsFirst") {
+	case "", "false":
+	case "true":
+		sortOpts.DirsFirst = true
+	default:
+		return "", sortOpts, errors.New(translate(lang, "error.bad_dirs_first"))
+	}
+
+	return dirPath, sortOpts, nil
+}
+
+// parseDepth - функция для чтения глубины вывода из параметра depth. Без параметра используется --list-depth,
+// 0 означает вывод всего дерева, вложенные элементы выводятся с именем относительно root.
+func parseDepth(r *http.Request, lang string) (int, error) {
+	value := r.URL.Query().Get("depth")
+	if value == "" {
+		return config.ListDepth, nil
+	}
+	depth, err := strconv.Atoi(value)
+	if err != nil || depth < 0 {
+		return 0, errors.New(translate(lang, "error.bad_depth"))
+	}
+	return depth, nil
+}
+
+// parseBinary - функция для чтения параметра binary: при true размеры выводятся в двоичных единицах (КиБ, МиБ).
+func parseBinary(r *http.Request, lang string) (bool, error) {
+	switch r.URL.Query().Get("binary") {
+	case "", "false":
+		return false, nil
+	case "true":
+		return true, nil
+	}
+	return false, errors.New(translate(lang, "error.bad_binary"))
 }
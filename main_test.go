@@ -0,0 +1,23 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleRootRedirectsToDefaultRoot(t *testing.T) {
+	saved := config
+	t.Cleanup(func() { config = saved })
+	config.DefaultRoot = "/srv/data dir"
+
+	rec := httptest.NewRecorder()
+	handleRoot(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusFound)
+	}
+	if got, want := rec.Header().Get("Location"), "/?root=%2Fsrv%2Fdata+dir&sort=asc"; got != want {
+		t.Fatalf("Location = %q, want %q", got, want)
+	}
+}
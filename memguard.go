@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// memoryGuardInterval - период проверки занятой памяти.
+const memoryGuardInterval = 10 * time.Second
+
+// memoryGuard - функция для фонового контроля памяти. Если куча превышает лимит,
+// вытесняет половину записей кэша сканирования и кэша ответов и запускает сборку мусора.
+func memoryGuard(ctx context.Context, maxMemory uint64) {
+	ticker := time.NewTicker(memoryGuardInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		var before runtime.MemStats
+		runtime.ReadMemStats(&before)
+		if before.HeapAlloc <= maxMemory || scanCache.Len()+responseCache.Len() == 0 {
+			continue
+		}
+
+		evicted := scanCache.EvictHalf() + responseCache.EvictHalf()
+		runtime.GC()
+
+		var after runtime.MemStats
+		runtime.ReadMemStats(&after)
+		cacheEvictionsTotal.Add(float64(evicted))
+		config.Logger.Warn(fmt.Sprintf("Нехватка памяти: вытеснено записей кэша: %d, память до: %d байт, после: %d байт",
+			evicted, before.HeapAlloc, after.HeapAlloc))
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	filesystem "filesystem/file_system"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// cacheEvictionsTotal - счетчик записей, вытесненных из кэша при нехватке памяти.
+var cacheEvictionsTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "filesystem_cache_evictions_total",
+	Help: "Количество записей, вытесненных из кэша сканирования и кэша ответов при нехватке памяти.",
+})
+
+// goroutineLimitHitsTotal - счетчик переходов сканирования на последовательную обработку из-за лимита горутин.
+var goroutineLimitHitsTotal = promauto.NewCounterFunc(prometheus.CounterOpts{
+	Name: "filesystem_goroutine_limit_hits_total",
+	Help: "Количество переходов сканирования на последовательную обработку из-за лимита горутин.",
+}, func() float64 {
+	return float64(filesystem.GoroutineLimitHits())
+})
+
+// sloViolationsTotal - счетчик ответов, время которых превысило --slo-max-ms.
+var sloViolationsTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "filesystem_slo_violations_total",
+	Help: "Количество ответов, время которых превысило допустимое.",
+})
+
+// dirSizeErrorsTotal - счетчик подсчетов размера директорий, прерванных ошибкой обхода.
+var dirSizeErrorsTotal = promauto.NewCounterFunc(prometheus.CounterOpts{
+	Name: "filesystem_dir_size_errors_total",
+	Help: "Количество подсчетов размера директорий, прерванных ошибкой обхода.",
+}, func() float64 {
+	return float64(filesystem.DirSizeErrors())
+})
+
+// httpRequestsTotal - счетчик обработанных запросов по обработчику и коду ответа.
+var httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "filesystem_http_requests_total",
+	Help: "Количество обработанных HTTP-запросов.",
+}, []string{"handler", "code"})
+
+// httpRequestDuration - гистограмма времени обработки запросов по обработчику.
+var httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "filesystem_http_request_duration_seconds",
+	Help:    "Время обработки HTTP-запросов в секундах.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"handler"})
+
+// httpRequestsInFlight - количество запросов, обрабатываемых в данный момент.
+var httpRequestsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "filesystem_http_requests_in_flight",
+	Help: "Количество HTTP-запросов, обрабатываемых в данный момент.",
+})
+
+// metricsMiddleware - middleware для сбора метрик запросов. В качестве метки handler используется
+// шаблон, под который запрос попал в http.DefaultServeMux, чтобы число меток не зависело от путей.
+func metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, pattern := http.DefaultServeMux.Handler(r)
+		if pattern == "" {
+			pattern = "other"
+		}
+
+		httpRequestsInFlight.Inc()
+		defer httpRequestsInFlight.Dec()
+
+		start := time.Now()
+		recorder := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r)
+
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		httpRequestsTotal.WithLabelValues(pattern, strconv.Itoa(status)).Inc()
+		httpRequestDuration.WithLabelValues(pattern).Observe(time.Since(start).Seconds())
+	})
+}
+
+// startMetricsServer - функция для запуска отдельного сервера метрик на addr, чтобы /metrics
+// можно было открыть только во внутренней сети. Стандартные метрики среды выполнения Go
+// и процесса уже зарегистрированы в prometheus.DefaultRegisterer.
+func startMetricsServer(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	go func() {
+		config.Logger.Info("Метрики доступны на", addr+"/metrics")
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			config.Logger.Fatal(fmt.Sprintf("Ошибка при запуске сервера метрик: %v", err))
+		}
+	}()
+}
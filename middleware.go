@@ -0,0 +1,296 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// adminTokenMiddleware - middleware для защиты служебных обработчиков токеном администратора.
+// Токен передается в заголовке "Authorization: Bearer <токен>" или "X-Admin-Token".
+func adminTokenMiddleware(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Без настроенного токена служебные обработчики недоступны.
+			if token == "" {
+				writeJSONError(w, http.StatusForbidden, "токен администратора не настроен")
+				return
+			}
+
+			provided := r.Header.Get("X-Admin-Token")
+			if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+				provided = strings.TrimPrefix(auth, "Bearer ")
+			}
+
+			if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				writeJSONError(w, http.StatusUnauthorized, "неверный токен администратора")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// responseRecorder - обертка над http.ResponseWriter, запоминающая код ответа и объем тела.
+// Flush и Push передаются исходному ResponseWriter, чтобы потоковый режим продолжал работать.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int   // status - отправленный код ответа (0 - заголовки еще не отправлены).
+	bytes  int64 // bytes - количество записанных байт тела.
+}
+
+// WriteHeader - метод для отправки заголовков с сохранением кода ответа.
+func (w *responseRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write - метод для записи тела ответа с подсчетом байт.
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush - метод для отправки буферизованных данных клиенту.
+func (w *responseRecorder) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Push - метод для отправки ресурсов по HTTP/2, если его поддерживает исходный ResponseWriter.
+func (w *responseRecorder) Push(target string, opts *http.PushOptions) error {
+	if pusher, ok := w.ResponseWriter.(http.Pusher); ok {
+		return pusher.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+// timeoutMiddleware - middleware для ограничения времени обработки запроса. Контекст запроса
+// отменяется через d, что прерывает обход директорий. Если обработчик к этому моменту ничего
+// не отправил, клиент получает 503.
+func timeoutMiddleware(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			recorder := &responseRecorder{ResponseWriter: w}
+			next.ServeHTTP(recorder, r.WithContext(ctx))
+
+			if recorder.status == 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				writeJSONError(w, http.StatusServiceUnavailable, "превышено время обработки запроса")
+			}
+		})
+	}
+}
+
+// recoveryMiddleware - middleware для перехвата паники в обработчиках и middleware внутри него. Паника
+// записывается в лог со стеком, а клиент получает 500 с идентификатором запроса вместо разорванного
+// соединения. Снаружи остаются только заголовки безопасности, метрики и журнал доступа.
+func recoveryMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			// http.ErrAbortHandler - штатный способ прервать ответ, его обрабатывает сам net/http.
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+			// requestIDMiddleware работает внутри и к моменту паники уже выставил X-Request-ID в заголовках
+			// ответа, а контекст с идентификатором сюда не доходит, поэтому берем его из заголовка.
+			id := w.Header().Get("X-Request-ID")
+			config.Logger.WithRequestID(id).Error(fmt.Sprintf("Паника при обработке %s %s: %v\n%s", r.Method, r.URL.Path, rec, debug.Stack()))
+			writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: "internal server error", RequestID: id})
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// basicAuthMiddleware - middleware для защиты сервера логином и паролем (HTTP Basic). Статические файлы
+// из /web/static/ доступны без входа, чтобы страница могла загрузить стили, /healthz и /readyz - чтобы
+// их могли опрашивать Kubernetes и балансировщики. При заданном --api-key обработчики /api/ защищены
+// ключом и логин для них не запрашивается.
+func basicAuthMiddleware(user, password string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, "/web/static/") || isProbePath(r.URL.Path) || (config.APIKey != "" && isAPIPath(r.URL.Path)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Сравниваем обе части, даже если первая не совпала, чтобы время ответа не выдавало логин.
+			providedUser, providedPassword, _ := r.BasicAuth()
+			userOK := subtle.ConstantTimeCompare([]byte(providedUser), []byte(user))
+			passwordOK := subtle.ConstantTimeCompare([]byte(providedPassword), []byte(password))
+			if userOK&passwordOK != 1 {
+				w.Header().Set("WWW-Authenticate", `Basic realm="filesystem"`)
+				writeJSONError(w, http.StatusUnauthorized, "требуется вход")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// apiKeyMiddleware - middleware для защиты обработчиков /api/ ключом из заголовка X-Api-Key.
+// Страницы для браузера остаются доступными без ключа.
+func apiKeyMiddleware(key string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isAPIPath(r.URL.Path) && subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Api-Key")), []byte(key)) != 1 {
+				writeJSONError(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isAPIPath - функция для проверки, относится ли путь запроса к JSON API.
+func isAPIPath(path string) bool {
+	return path == "/api" || strings.HasPrefix(path, "/api/")
+}
+
+// corsAllowedHeaders - заголовки запроса, которые разрешено передавать с другого источника.
+const corsAllowedHeaders = "Content-Type, Authorization, X-Api-Key, X-Admin-Token"
+
+// corsMiddleware - middleware для ответов на запросы с других источников (CORS). Источник из списка
+// origins возвращается в Access-Control-Allow-Origin, "*" разрешает любой. Предварительные запросы
+// OPTIONS получают 204 без передачи дальше, чтобы на них не срабатывала проверка входа.
+func corsMiddleware(origins []string, methods string) func(http.Handler) http.Handler {
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		allowed[origin] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			w.Header().Add("Vary", "Origin")
+			if origin != "" && (allowed["*"] || allowed[origin]) {
+				if allowed["*"] {
+					w.Header().Set("Access-Control-Allow-Origin", "*")
+				} else {
+					w.Header().Set("Access-Control-Allow-Origin", origin)
+				}
+				w.Header().Set("Access-Control-Allow-Methods", methods)
+				w.Header().Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+
+				if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// adminIPWhitelistMiddleware - middleware для ограничения доступа к служебным обработчикам по IP-адресу.
+// Пустой список сетей доступ не ограничивает. Ошибка в записи сети прерывает запуск сервера.
+func adminIPWhitelistMiddleware(cidrs []string) func(http.Handler) http.Handler {
+	var networks []*net.IPNet
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			config.Logger.Fatal(fmt.Sprintf("неправильно указана сеть %q в --admin-cidr: %v", cidr, err))
+		}
+		networks = append(networks, network)
+	}
+
+	return func(next http.Handler) http.Handler {
+		if len(networks) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := net.ParseIP(clientIP(r))
+			for _, network := range networks {
+				if ip != nil && network.Contains(ip) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			writeJSONError(w, http.StatusForbidden, "доступ с этого адреса запрещен")
+		})
+	}
+}
+
+// clientIP - функция для получения IP-адреса клиента. Заголовки X-Forwarded-For и X-Real-IP
+// учитываются только при --trust-proxy, иначе их может подделать сам клиент.
+func clientIP(r *http.Request) string {
+	if config.TrustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			return strings.TrimSpace(first)
+		}
+		if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+			return strings.TrimSpace(realIP)
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// adminHandler - функция для защиты служебного обработчика списком сетей и токеном администратора.
+func adminHandler(handler http.HandlerFunc) http.Handler {
+	return adminIPWhitelistMiddleware(config.AdminCIDRs)(adminTokenMiddleware(config.AdminToken)(handler))
+}
+
+// SecurityHeaders - структура с заголовками безопасности. Пустое значение отключает заголовок.
+type SecurityHeaders struct {
+	ContentTypeOptions    string `json:"contentTypeOptions"`    // ContentTypeOptions - значение X-Content-Type-Options.
+	FrameOptions          string `json:"frameOptions"`          // FrameOptions - значение X-Frame-Options.
+	XSSProtection         string `json:"xssProtection"`         // XSSProtection - значение X-XSS-Protection.
+	ReferrerPolicy        string `json:"referrerPolicy"`        // ReferrerPolicy - значение Referrer-Policy.
+	ContentSecurityPolicy string `json:"contentSecurityPolicy"` // ContentSecurityPolicy - значение Content-Security-Policy.
+
+	StrictTransportSecurity string `json:"strictTransportSecurity"` // StrictTransportSecurity - значение Strict-Transport-Security, отправляется только по HTTPS.
+}
+
+// securityHeadersMiddleware - middleware для установки заголовков безопасности на каждый ответ.
+// Подключается снаружи recoveryMiddleware, чтобы заголовки получал и ответ 500 после паники.
+// Strict-Transport-Security добавляется только при tlsEnabled: по HTTP браузеры его игнорируют.
+func securityHeadersMiddleware(headers SecurityHeaders, tlsEnabled bool) func(http.Handler) http.Handler {
+	values := map[string]string{
+		"X-Content-Type-Options":  headers.ContentTypeOptions,
+		"X-Frame-Options":         headers.FrameOptions,
+		"X-XSS-Protection":        headers.XSSProtection,
+		"Referrer-Policy":         headers.ReferrerPolicy,
+		"Content-Security-Policy": headers.ContentSecurityPolicy,
+	}
+	if tlsEnabled {
+		values["Strict-Transport-Security"] = headers.StrictTransportSecurity
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for name, value := range values {
+				if value != "" {
+					w.Header().Set(name, value)
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
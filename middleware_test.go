@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// panicHandler - обработчик, который всегда паникует.
+var panicHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	panic("boom")
+})
+
+func TestRecoveryMiddleware(t *testing.T) {
+	saved := config
+	t.Cleanup(func() { config = saved })
+	config.Logger = NewTextLogger(io.Discard)
+
+	// Порядок как в startHTTPServer: requestID и gzip внутри перехвата паники.
+	handler := recoveryMiddleware(gzipMiddleware(requestIDMiddleware(panicHandler)))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/files", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("X-Request-ID", "panic-test")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q on an uncompressed error body", got)
+	}
+	var resp ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("body is not JSON: %v: %s", err, rec.Body)
+	}
+	if resp.RequestID != "panic-test" {
+		t.Errorf("requestId = %q, want %q", resp.RequestID, "panic-test")
+	}
+}
+
+func TestSecurityHeadersOnPanic(t *testing.T) {
+	saved := config
+	t.Cleanup(func() { config = saved })
+	config.Logger = NewTextLogger(io.Discard)
+
+	headers := SecurityHeaders{ContentTypeOptions: "nosniff", FrameOptions: "DENY"}
+	handler := securityHeadersMiddleware(headers, false)(recoveryMiddleware(panicHandler))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if got := rec.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Errorf("X-Content-Type-Options = %q, want nosniff", got)
+	}
+	if got := rec.Header().Get("X-Frame-Options"); got != "DENY" {
+		t.Errorf("X-Frame-Options = %q, want DENY", got)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Форматы ответа обработчика файловой системы.
+const (
+	formatHTML = "html"
+	formatJSON = "json"
+)
+
+// negotiateFormat - функция для выбора формата ответа. Параметр format в запросе имеет приоритет,
+// иначе формат выбирается по заголовку Accept с учетом весов q. При равных весах выбирается HTML.
+func negotiateFormat(r *http.Request) string {
+	switch r.URL.Query().Get("format") {
+	case formatJSON:
+		return formatJSON
+	case formatHTML:
+		return formatHTML
+	}
+
+	var htmlQ, jsonQ float64
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, q := parseAcceptPart(part)
+		switch mediaType {
+		case "text/html":
+			htmlQ = maxFloat(htmlQ, q)
+		case "application/json":
+			jsonQ = maxFloat(jsonQ, q)
+		}
+	}
+
+	if jsonQ > htmlQ {
+		return formatJSON
+	}
+	return formatHTML
+}
+
+// parseAcceptPart - функция для разбора одного элемента заголовка Accept на тип и вес q.
+func parseAcceptPart(part string) (string, float64) {
+	params := strings.Split(part, ";")
+	mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+	q := 1.0
+	for _, param := range params[1:] {
+		key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || strings.TrimSpace(key) != "q" {
+			continue
+		}
+		if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			q = parsed
+		}
+	}
+	return mediaType, q
+}
+
+// maxFloat - вспомогательная функция для выбора большего из двух чисел.
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// writePage - функция для отправки данных страницы в выбранном формате.
+// В формате JSON выводится список файлов в обертке envelope.
+func writePage(w http.ResponseWriter, format, envelope string, data PageData) {
+	if format == formatJSON {
+		meta := ResponseMeta{
+			Total:        len(data.FileList),
+			Page:         1,
+			Elapsed:      data.EndRaw,
+			Truncated:    data.Truncated,
+			ScannedBytes: data.ScannedBytes,
+			Errors:       data.Errors,
+			Skipped:      SkippedMeta{Count: data.SkippedCount, Bytes: data.SkippedBytes},
+			Degraded:     data.Degraded,
+			Reason:       data.DegradedReason,
+		}
+		jsonResponse(w, data.FileList, meta, envelope)
+		return
+	}
+	renderTemplate(w, data)
+}
+
+// handleFiles - функция-обработчик JSON API со списком файлов. Принимает те же параметры, что и
+// главная страница, и всегда отвечает в формате JSON.
+func handleFiles(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	query.Set("format", formatJSON)
+
+	apiRequest := r.Clone(r.Context())
+	apiRequest.URL.RawQuery = query.Encode()
+	handleFileSystem(w, apiRequest)
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWritePageScannedBytes(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writePage(rec, formatJSON, envelopeWrapped, PageData{Truncated: true, ScannedBytes: 10e9})
+
+	var resp struct {
+		Meta ResponseMeta `json:"meta"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("body is not JSON: %v: %s", err, rec.Body)
+	}
+	if !resp.Meta.Truncated || resp.Meta.ScannedBytes != 10e9 {
+		t.Errorf("meta = %+v, want truncated with scannedBytes 1e10", resp.Meta)
+	}
+}
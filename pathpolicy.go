@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// forbiddenPathError - ошибка запроса директории, закрытой настройками --forbidden или --root-jail.
+type forbiddenPathError struct {
+	message string
+}
+
+// Error - метод для вывода текста ошибки.
+func (e *forbiddenPathError) Error() string {
+	return e.message
+}
+
+// checkPath - функция для проверки, разрешен ли просмотр path. Путь приводится к абсолютному виду,
+// а символические ссылки разыменовываются, чтобы через них нельзя было выйти за пределы --root-jail
+// или попасть в запрещенную директорию. Ошибка возвращается на языке lang.
+func checkPath(path, lang string) error {
+	if len(config.Forbidden) == 0 && config.RootJail == "" {
+		return nil
+	}
+
+	candidates := []string{}
+	if abs, err := filepath.Abs(path); err == nil {
+		candidates = append(candidates, abs)
+		if resolved, err := filepath.EvalSymlinks(abs); err == nil && resolved != abs {
+			candidates = append(candidates, resolved)
+		}
+	}
+
+	for _, candidate := range candidates {
+		if config.RootJail != "" && !isUnder(candidate, config.RootJail) {
+			return &forbiddenPathError{message: fmt.Sprintf(translate(lang, "error.path_forbidden"), path)}
+		}
+		for _, prefix := range config.Forbidden {
+			if isUnder(candidate, prefix) {
+				return &forbiddenPathError{message: fmt.Sprintf(translate(lang, "error.path_forbidden"), path)}
+			}
+		}
+	}
+	return nil
+}
+
+// isForbiddenPath - функция для проверки, закрыта ли директория path настройками --forbidden или --root-jail.
+// Используется при обходе вложенных уровней, где нужен только признак, без текста ошибки.
+func isForbiddenPath(path string) bool {
+	return checkPath(path, "") != nil
+}
+
+// isUnder - функция для проверки, совпадает ли path с prefix или находится внутри него.
+// Сравнение идет по границам элементов пути: /etc не считается префиксом /etcetera.
+func isUnder(path, prefix string) bool {
+	prefix = filepath.Clean(prefix)
+	if path == prefix {
+		return true
+	}
+	if !strings.HasSuffix(prefix, string(filepath.Separator)) {
+		prefix += string(filepath.Separator)
+	}
+	return strings.HasPrefix(path, prefix)
+}
+
+// parsePathList - функция для разбора списка путей через запятую в абсолютные пути без завершающих разделителей.
+func parsePathList(value string) ([]string, error) {
+	var paths []string
+	for _, path := range strings.Split(value, ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		if !filepath.IsAbs(path) {
+			return nil, fmt.Errorf("путь %q должен быть абсолютным", path)
+		}
+		paths = append(paths, filepath.Clean(path))
+	}
+	return paths, nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// writePidFile - функция для записи PID процесса в файл path.
+// Если файл уже существует, он перезаписывается с предупреждением при overwrite, иначе возвращается ошибка.
+func writePidFile(path string, overwrite bool) error {
+	dir := filepath.Dir(path)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return fmt.Errorf("директория для pid-файла %s не существует", dir)
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		if !overwrite {
+			return fmt.Errorf("pid-файл %s уже существует, возможно, сервер уже запущен (используйте --pidfile-overwrite)", path)
+		}
+		config.Logger.Info("Предупреждение: pid-файл уже существует и будет перезаписан:", path)
+	}
+
+	return os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644)
+}
+
+// removePidFile - функция для удаления pid-файла при завершении работы сервера.
+func removePidFile(path string) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		config.Logger.Error("Ошибка при удалении pid-файла:", err)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+)
+
+// defaultPreviewBytes - ограничение --preview-bytes по умолчанию.
+const defaultPreviewBytes = 64_000
+
+// handlePreview - функция-обработчик для предпросмотра текстового файла. Выводится не больше
+// --preview-bytes байт в text/plain; если файл обрезан, передается заголовок X-Preview-Truncated.
+// Двоичные файлы не выводятся. Путь проверяется по тем же правилам, что и при скачивании.
+func handlePreview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeJSONError(w, http.StatusMethodNotAllowed, "метод не поддерживается")
+		return
+	}
+
+	if r.URL.Query().Get("path") == "" {
+		writeJSONError(w, http.StatusBadRequest, "не указан path")
+		return
+	}
+	filePath := filepath.Clean(r.URL.Query().Get("path"))
+	if err := checkPath(filePath, resolveLang(r)); err != nil {
+		writeJSONError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		writeJSONError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	defer file.Close()
+
+	if info, err := file.Stat(); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	} else if !info.Mode().IsRegular() {
+		writeJSONError(w, http.StatusBadRequest, "path указывает не на обычный файл")
+		return
+	}
+
+	// Читаем на байт больше ограничения, чтобы узнать, обрезан ли файл.
+	data, err := io.ReadAll(io.LimitReader(file, config.PreviewBytes+1))
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	truncated := int64(len(data)) > config.PreviewBytes
+	if truncated {
+		data = trimPartialRune(data[:config.PreviewBytes])
+	}
+
+	if !isText(data) {
+		writeJSONError(w, http.StatusUnsupportedMediaType, "файл не является текстовым")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if truncated {
+		w.Header().Set("X-Preview-Truncated", "true")
+	}
+	_, _ = w.Write(data)
+}
+
+// isText - функция для проверки, похожи ли данные на текст: корректный UTF-8 без нулевых байт.
+func isText(data []byte) bool {
+	return utf8.Valid(data) && bytes.IndexByte(data, 0) < 0
+}
+
+// trimPartialRune - функция для отбрасывания символа UTF-8, разрезанного ограничением длины.
+func trimPartialRune(data []byte) []byte {
+	for i := 1; i < utf8.UTFMax && i <= len(data); i++ {
+		if utf8.RuneStart(data[len(data)-i]) {
+			if !utf8.FullRune(data[len(data)-i:]) {
+				return data[:len(data)-i]
+			}
+			break
+		}
+	}
+	return data
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	filesystem "filesystem/file_system"
+)
+
+// prewarmMu - мьютекс для доступа к состоянию прогрева.
+var prewarmMu sync.Mutex
+
+// prewarmState - состояние прогрева кэша: путь -> завершен ли его прогрев.
+var prewarmState = map[string]bool{}
+
+// PrewarmStatus - структура ответа для путей, прогрев которых еще не завершен.
+type PrewarmStatus struct {
+	Status string `json:"status"` // Status - текущее состояние прогрева.
+	Warmed int    `json:"warmed"` // Warmed - количество уже прогретых путей.
+	Total  int    `json:"total"`  // Total - общее количество путей для прогрева.
+}
+
+// parsePrewarmPaths - функция для разбора списка путей, перечисленных через запятую.
+func parsePrewarmPaths(value string) []string {
+	var paths []string
+	for _, path := range strings.Split(value, ",") {
+		path = strings.TrimSpace(path)
+		if path != "" {
+			paths = append(paths, filepath.Clean(path))
+		}
+	}
+	return paths
+}
+
+// startPrewarm - функция для фонового прогрева кэша сканирования указанными путями.
+func startPrewarm(paths []string) {
+	prewarmMu.Lock()
+	for _, path := range paths {
+		prewarmState[path] = false
+	}
+	prewarmMu.Unlock()
+
+	go func() {
+		for _, path := range paths {
+			fileList, err := filesystem.ListDirByReadDir(context.Background(), path, config.scanOptions())
+			if err != nil {
+				config.Logger.Error(fmt.Sprintf("Ошибка прогрева директории %s: %v", path, err))
+			} else {
+				filesystem.SortFileList(fileList, filesystem.SortOptions{Key: filesystem.SortBySize, Order: "asc"})
+				scanCache.Put(path, fileList)
+			}
+
+			prewarmMu.Lock()
+			prewarmState[path] = true
+			prewarmMu.Unlock()
+			config.Logger.Info("Прогрев директории завершен:", path)
+		}
+	}()
+}
+
+// prewarmedList - функция для получения сохраненного результата сканирования прогретой директории,
+// если он не старше --prewarm-ttl. Для остальных директорий возвращает false.
+func prewarmedList(path string) ([]filesystem.FileInfo, bool) {
+	if config.PrewarmTTL <= 0 {
+		return nil, false
+	}
+	prewarmMu.Lock()
+	_, ok := prewarmState[filepath.Clean(path)]
+	prewarmMu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	return scanCache.GetFresh(filepath.Clean(path), config.PrewarmTTL)
+}
+
+// checkPrewarm - функция для проверки состояния прогрева пути. Возвращает false, если прогрев еще идет.
+func checkPrewarm(path string) (PrewarmStatus, bool) {
+	prewarmMu.Lock()
+	defer prewarmMu.Unlock()
+
+	done, ok := prewarmState[filepath.Clean(path)]
+	if !ok || done {
+		return PrewarmStatus{}, true
+	}
+
+	status := PrewarmStatus{Status: "warming", Total: len(prewarmState)}
+	for _, warmed := range prewarmState {
+		if warmed {
+			status.Warmed++
+		}
+	}
+	return status, false
+}
+
+// writePrewarmStatus - вспомогательная функция для ответа 503 на запрос прогреваемого пути.
+func writePrewarmStatus(w http.ResponseWriter, status PrewarmStatus) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", "5")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		config.Logger.Error("Ошибка при кодировании данных в JSON:", err)
+	}
+}
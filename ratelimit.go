@@ -0,0 +1,81 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimiter - ограничитель частоты запросов по алгоритму token bucket. Жетоны хранятся
+// в буферизованном канале емкостью burst и пополняются тикером со скоростью rate в секунду.
+type RateLimiter struct {
+	tokens     chan struct{}
+	retryAfter int // retryAfter - через сколько секунд появится следующий жетон, для заголовка Retry-After.
+}
+
+// minRefillInterval - минимальный интервал пополнения корзины.
+const minRefillInterval = time.Millisecond
+
+// NewRateLimiter - функция для создания ограничителя на rate запросов в секунду с запасом burst.
+// Корзина изначально заполнена, пополнение идет в фоне до завершения процесса.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	limiter := &RateLimiter{
+		tokens:     make(chan struct{}, burst),
+		retryAfter: int(math.Ceil(1 / rate)),
+	}
+	for i := 0; i < burst; i++ {
+		limiter.tokens <- struct{}{}
+	}
+
+	// Тикер чаще minRefillInterval только нагружает процессор (а при rate больше 1e9 интервал
+	// округлился бы до нуля, на котором time.NewTicker паникует), поэтому при большой частоте
+	// за один тик добавляется несколько жетонов.
+	perTick := math.Max(1, math.Ceil(rate*minRefillInterval.Seconds()))
+	interval := time.Duration(perTick / rate * float64(time.Second))
+	// Больше burst за тик все равно не поместится в корзину.
+	perTick = math.Min(perTick, float64(burst))
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+		refill:
+			for i := 0; i < int(perTick); i++ {
+				select {
+				case limiter.tokens <- struct{}{}:
+				default: // Корзина полна.
+					break refill
+				}
+			}
+		}
+	}()
+	return limiter
+}
+
+// Allow - метод для получения жетона без ожидания. Возвращает false, если жетонов нет.
+func (l *RateLimiter) Allow() bool {
+	select {
+	case <-l.tokens:
+		return true
+	default:
+		return false
+	}
+}
+
+// rateLimitMiddleware - middleware для ограничения частоты запросов. При превышении
+// отвечает 429 с заголовком Retry-After.
+func rateLimitMiddleware(limiter *RateLimiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !limiter.Allow() {
+				w.Header().Set("Retry-After", strconv.Itoa(limiter.retryAfter))
+				writeJSONError(w, http.StatusTooManyRequests, "слишком много запросов, повторите позже")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	// Пополнение раз в 1000 секунд, чтобы за время теста жетоны не появлялись.
+	handler := rateLimitMiddleware(NewRateLimiter(0.001, 2))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	for i, want := range []int{http.StatusNoContent, http.StatusNoContent, http.StatusTooManyRequests} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/files", nil))
+		if rec.Code != want {
+			t.Fatalf("request %d: status = %d, want %d", i, rec.Code, want)
+		}
+		if want == http.StatusTooManyRequests {
+			if got := rec.Header().Get("Retry-After"); got != "1000" {
+				t.Fatalf("Retry-After = %q, want 1000", got)
+			}
+		}
+	}
+}
+
+func TestRateLimiterRefill(t *testing.T) {
+	limiter := NewRateLimiter(100, 1)
+	if !limiter.Allow() {
+		t.Fatal("initial token missing")
+	}
+	if limiter.Allow() {
+		t.Fatal("burst of 1 allowed two requests")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for !limiter.Allow() {
+		if time.Now().After(deadline) {
+			t.Fatal("token was not refilled within a second at 100 rps")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestNewRateLimiterHugeRate(t *testing.T) {
+	// Интервал пополнения меньше наносекунды не должен приводить к панике тикера,
+	// а большая частота - к потере жетонов.
+	limiter := NewRateLimiter(1e12, 1000)
+	for i := 0; i < 1000; i++ {
+		limiter.Allow()
+	}
+	time.Sleep(10 * minRefillInterval)
+	for i := 0; i < 1000; i++ {
+		if !limiter.Allow() {
+			t.Fatalf("only %d of 1000 tokens refilled", i)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// BulkRenameRequest - структура запроса на массовое переименование файлов.
+type BulkRenameRequest struct {
+	Dir      string `json:"dir"`      // Dir - директория с файлами.
+	Pattern  string `json:"pattern"`  // Pattern - шаблон поиска файлов, например "*.log".
+	Template string `json:"template"` // Template - шаблон нового имени для text/template.
+}
+
+// RenameContext - данные, доступные в шаблоне нового имени.
+type RenameContext struct {
+	Name     string // Name - имя файла без расширения.
+	BaseName string // BaseName - полное имя файла.
+	Ext      string // Ext - расширение вместе с точкой.
+	Index    int    // Index - порядковый номер файла среди найденных, начиная с 1.
+}
+
+// RenamedFile - структура с результатом переименования одного файла.
+type RenamedFile struct {
+	From string `json:"from"` // From - прежний путь.
+	To   string `json:"to"`   // To - новый путь.
+}
+
+// BulkRenameResponse - структура ответа массового переименования.
+type BulkRenameResponse struct {
+	Renamed []RenamedFile `json:"renamed"`          // Renamed - переименованные файлы.
+	Errors  []string      `json:"errors,omitempty"` // Errors - ошибки для отдельных файлов.
+	DryRun  bool          `json:"dryRun"`           // DryRun - файлы не переименовывались, выведен только план.
+}
+
+// handleBulkRename - функция-обработчик для массового переименования файлов по шаблону.
+// Работает только при --rename-enabled и доступен как служебный обработчик (adminHandler). Тело
+// принимается только с Content-Type: application/json, чтобы запрос нельзя было отправить простой
+// HTML-формой с другого сайта. С параметром dry-run=1 лишь выводит план переименования.
+func handleBulkRename(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSONError(w, http.StatusMethodNotAllowed, "метод не поддерживается")
+		return
+	}
+	if !config.RenameEnabled {
+		writeJSONError(w, http.StatusForbidden, "переименование отключено, запустите сервер с --rename-enabled")
+		return
+	}
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		writeJSONError(w, http.StatusUnsupportedMediaType, "тело запроса должно быть в формате application/json")
+		return
+	}
+
+	var req BulkRenameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "неправильный формат запроса: "+err.Error())
+		return
+	}
+	if req.Dir == "" || req.Pattern == "" || req.Template == "" {
+		writeJSONError(w, http.StatusBadRequest, "не указаны dir, pattern или template")
+		return
+	}
+	if err := checkPath(req.Dir, resolveLang(r)); err != nil {
+		writeJSONError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	if strings.ContainsAny(req.Pattern, `/\`) {
+		writeJSONError(w, http.StatusBadRequest, "pattern не должен содержать разделителей пути")
+		return
+	}
+
+	tmpl, err := template.New("rename").Parse(req.Template)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "неправильный шаблон: "+err.Error())
+		return
+	}
+	matches, err := filepath.Glob(filepath.Join(req.Dir, req.Pattern))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "неправильный pattern: "+err.Error())
+		return
+	}
+
+	response := BulkRenameResponse{Renamed: []RenamedFile{}, DryRun: r.URL.Query().Get("dry-run") == "1"}
+	for i, from := range matches {
+		to, err := renderRenameTarget(tmpl, from, i+1)
+		if err == nil && !response.DryRun {
+			err = renameFile(from, to)
+			journalMove(r, from, to, err)
+		}
+		if err != nil {
+			response.Errors = append(response.Errors, fmt.Sprintf("%s: %v", from, err))
+			continue
+		}
+		response.Renamed = append(response.Renamed, RenamedFile{From: from, To: to})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// journalMove - функция для записи переименования в журнал изменений. Ошибка записи журнала
+// не отменяет уже выполненное переименование и только выводится в журнал сервера.
+func journalMove(r *http.Request, from, to string, renameErr error) {
+	entry := JournalEntry{Op: JournalOpMove, Src: from, Dst: to, User: accessLogUser(r), Status: JournalStatusOK}
+	// После успешного переименования файл уже находится по новому пути.
+	path := to
+	if renameErr != nil {
+		entry.Status = JournalStatusFailed + ": " + renameErr.Error()
+		path = from
+	}
+	if info, err := os.Lstat(path); err == nil {
+		entry.Size = info.Size()
+	}
+	if err := journal.Append(entry); err != nil {
+		requestLogger(r).Warn("Ошибка записи в журнал изменений:", err)
+	}
+}
+
+// renderRenameTarget - функция для вычисления нового пути файла по шаблону.
+// Новое имя должно остаться в той же директории.
+func renderRenameTarget(tmpl *template.Template, from string, index int) (string, error) {
+	baseName := filepath.Base(from)
+	ext := filepath.Ext(baseName)
+	data := RenameContext{
+		Name:     strings.TrimSuffix(baseName, ext),
+		BaseName: baseName,
+		Ext:      ext,
+		Index:    index,
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	newName := strings.TrimSpace(buf.String())
+	if newName == "" || newName == "." || newName == ".." || strings.ContainsAny(newName, `/\`) {
+		return "", fmt.Errorf("недопустимое новое имя %q", newName)
+	}
+	return filepath.Join(filepath.Dir(from), newName), nil
+}
+
+// renameFile - функция для переименования файла без перезаписи существующего.
+func renameFile(from, to string) error {
+	if from == to {
+		return nil
+	}
+	if _, err := os.Lstat(to); err == nil {
+		return fmt.Errorf("файл %s уже существует", to)
+	}
+	return os.Rename(from, to)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// requestIDKey - тип ключа контекста для идентификатора запроса.
+type requestIDKey struct{}
+
+// maxRequestIDLength - максимальная длина идентификатора, принимаемого от клиента.
+const maxRequestIDLength = 128
+
+// requestIDMiddleware - middleware для присвоения запросу идентификатора. Идентификатор из заголовка
+// X-Request-ID используется повторно, иначе создается случайный. Он сохраняется в контексте
+// и возвращается клиенту в заголовке X-Request-ID.
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-ID")
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set("X-Request-ID", id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
+
+// requestID - функция для получения идентификатора запроса из контекста (пустой, если его нет).
+func requestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// requestLogger - функция для получения журнала, добавляющего идентификатор запроса к каждой записи.
+func requestLogger(r *http.Request) Logger {
+	return config.Logger.WithRequestID(requestID(r.Context()))
+}
+
+// newRequestID - функция для создания случайного идентификатора из 16 байт в шестнадцатеричном виде.
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// validRequestID - функция для проверки идентификатора от клиента: непустой, не длиннее maxRequestIDLength
+// и только из видимых ASCII-символов, чтобы через него нельзя было подделать строки лога.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bytes"
+	"container/list"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// cachedResponse - сохраненный ответ обработчика.
+type cachedResponse struct {
+	status  int
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+// responseEntry - запись кэша ответов.
+type responseEntry struct {
+	key  string
+	resp cachedResponse
+}
+
+// size - метод для оценки занимаемой записью памяти: ключ и тело ответа.
+func (e *responseEntry) size() int {
+	return len(e.key) + len(e.resp.body)
+}
+
+// ResponseCache - кэш готовых HTTP-ответов для одинаковых повторных запросов. Общий размер
+// ответов ограничен maxTotal, при переполнении вытесняются давно не использованные ответы.
+type ResponseCache struct {
+	mu       sync.Mutex
+	ttl      time.Duration            // ttl - время жизни ответа, если обработчик не указал max-age.
+	maxBytes int                      // maxBytes - максимальный размер сохраняемого тела ответа.
+	maxTotal int                      // maxTotal - максимальный общий размер сохраненных ответов (0 - без ограничения).
+	total    int                      // total - текущий общий размер сохраненных ответов.
+	order    *list.List               // order - порядок использования, в начале самые свежие ответы.
+	items    map[string]*list.Element // items - быстрый доступ к элементам списка по ключу запроса.
+
+	hits   int64 // hits - количество ответов, отданных из кэша.
+	misses int64 // misses - количество ответов, сформированных обработчиком.
+}
+
+// responseCache - кэш ответов сервера, nil если кэш отключен.
+var responseCache *ResponseCache
+
+// NewResponseCache - функция для создания кэша ответов.
+func NewResponseCache(ttl time.Duration, maxBytes, maxTotal int) *ResponseCache {
+	return &ResponseCache{
+		ttl:      ttl,
+		maxBytes: maxBytes,
+		maxTotal: maxTotal,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// get - метод для получения неустаревшего ответа по ключу. Устаревший ответ удаляется.
+func (c *ResponseCache) get(key string) (cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if ok && time.Now().After(elem.Value.(*responseEntry).resp.expires) {
+		c.remove(elem)
+		ok = false
+	}
+	if !ok {
+		atomic.AddInt64(&c.misses, 1)
+		return cachedResponse{}, false
+	}
+	c.order.MoveToFront(elem)
+	atomic.AddInt64(&c.hits, 1)
+	return elem.Value.(*responseEntry).resp, true
+}
+
+// put - метод для сохранения ответа. При превышении общего размера вытесняются давно не
+// использованные ответы; устаревшие ответы удаляются при обращении к ним или вытесняются так же.
+func (c *ResponseCache) put(key string, resp cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.remove(elem)
+	}
+	entry := &responseEntry{key: key, resp: resp}
+	c.items[key] = c.order.PushFront(entry)
+	c.total += entry.size()
+	for c.maxTotal > 0 && c.total > c.maxTotal && c.order.Len() > 0 {
+		c.remove(c.order.Back())
+	}
+}
+
+// remove - метод для удаления записи. Вызывается под мьютексом.
+func (c *ResponseCache) remove(elem *list.Element) {
+	entry := c.order.Remove(elem).(*responseEntry)
+	delete(c.items, entry.key)
+	c.total -= entry.size()
+}
+
+// Len - метод для получения количества сохраненных ответов. Для отключенного кэша (nil) - 0.
+func (c *ResponseCache) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// EvictHalf - метод для вытеснения половины ответов, начиная с давно не использованных.
+// Возвращает количество вытесненных ответов. Для отключенного кэша (nil) ничего не делает.
+func (c *ResponseCache) EvictHalf() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	count := (c.order.Len() + 1) / 2
+	for i := 0; i < count; i++ {
+		c.remove(c.order.Back())
+	}
+	return count
+}
+
+// HitRate - метод для получения доли запросов, обслуженных из кэша.
+func (c *ResponseCache) HitRate() float64 {
+	hits := atomic.LoadInt64(&c.hits)
+	total := hits + atomic.LoadInt64(&c.misses)
+	if total == 0 {
+		return 0
+	}
+	return float64(hits) / float64(total)
+}
+
+// responseTTL - функция для определения времени жизни ответа по заголовку Cache-Control.
+// Возвращает 0, если ответ нельзя кэшировать.
+func responseTTL(cacheControl string, defaultTTL time.Duration) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-store" || directive == "no-cache" || directive == "private":
+			return 0
+		case strings.HasPrefix(directive, "max-age="):
+			seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+			if err != nil || seconds <= 0 {
+				return 0
+			}
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return defaultTTL
+}
+
+// cacheRecorder - обертка над http.ResponseWriter, копирующая ответ для сохранения в кэш.
+type cacheRecorder struct {
+	http.ResponseWriter
+	status   int // status - код ответа, 0 если обработчик ничего не записал.
+	body     bytes.Buffer
+	maxBytes int
+	overflow bool // overflow - тело превысило лимит и не будет сохранено.
+}
+
+// WriteHeader - метод для записи кода ответа.
+func (rec *cacheRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+// Write - метод для записи тела ответа с копированием в буфер.
+func (rec *cacheRecorder) Write(p []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	if !rec.overflow {
+		if rec.body.Len()+len(p) > rec.maxBytes {
+			rec.overflow = true
+			rec.body.Reset()
+		} else {
+			rec.body.Write(p)
+		}
+	}
+	return rec.ResponseWriter.Write(p)
+}
+
+// Flush - метод для отправки буферизованных данных клиенту, если это поддерживает исходный writer.
+func (rec *cacheRecorder) Flush() {
+	if flusher, ok := rec.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// uncachedHeaders - заголовки, которые относятся к конкретной передаче ответа и не сохраняются в кэш:
+// сжатие выставляет gzipMiddleware при каждой отдаче, а X-Request-ID у каждого запроса свой.
+var uncachedHeaders = []string{"Content-Encoding", "Content-Length", "X-Request-ID"}
+
+// cacheableHeader - функция для получения копии заголовков ответа без uncachedHeaders. Из Vary
+// убирается Accept-Encoding: gzipMiddleware добавит его заново при отдаче из кэша.
+func cacheableHeader(header http.Header) http.Header {
+	clone := header.Clone()
+	for _, name := range uncachedHeaders {
+		clone.Del(name)
+	}
+	var vary []string
+	for _, value := range clone.Values("Vary") {
+		if !strings.EqualFold(strings.TrimSpace(value), "Accept-Encoding") {
+			vary = append(vary, value)
+		}
+	}
+	clone.Del("Vary")
+	for _, value := range vary {
+		clone.Add("Vary", value)
+	}
+	return clone
+}
+
+// responseCacheMiddleware - middleware для отдачи сохраненных ответов на повторяющиеся GET-запросы.
+// Ключом служит полный URL и заголовки Accept и Accept-Language.
+func responseCacheMiddleware(cache *ResponseCache) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := r.URL.String() + "\n" + r.Header.Get("Accept") + "\n" + r.Header.Get("Accept-Language")
+			if resp, ok := cache.get(key); ok {
+				for name, values := range resp.header {
+					if name == "Vary" {
+						// Vary дополняем, чтобы не потерять Accept-Encoding от gzipMiddleware.
+						w.Header()[name] = append(w.Header()[name], values...)
+						continue
+					}
+					w.Header()[name] = values
+				}
+				w.Header().Set("X-Cache", "HIT")
+				w.WriteHeader(resp.status)
+				_, _ = w.Write(resp.body)
+				return
+			}
+
+			rec := &cacheRecorder{ResponseWriter: w, maxBytes: cache.maxBytes}
+			next.ServeHTTP(rec, r)
+
+			// Пустой ответ прерванного запроса или запроса с истекшим таймаутом не сохраняем.
+			if rec.status != http.StatusOK || rec.overflow || r.Context().Err() != nil {
+				return
+			}
+			ttl := responseTTL(w.Header().Get("Cache-Control"), cache.ttl)
+			if ttl <= 0 {
+				return
+			}
+			cache.put(key, cachedResponse{
+				status:  rec.status,
+				header:  cacheableHeader(w.Header()),
+				body:    rec.body.Bytes(),
+				expires: time.Now().Add(ttl),
+			})
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestResponseCacheBehindGzip(t *testing.T) {
+	cache := NewResponseCache(time.Minute, 1<<20, 0)
+	// Порядок как в startHTTPServer: кэш внутри, requestID и gzip снаружи.
+	handler := gzipMiddleware(requestIDMiddleware(responseCacheMiddleware(cache)(gzipTestHandler)))
+
+	for i, id := range []string{"first", "second"} {
+		req := httptest.NewRequest(http.MethodGet, "/?root=/tmp", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		req.Header.Set("X-Request-ID", id)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if hit := rec.Header().Get("X-Cache") == "HIT"; hit != (i == 1) {
+			t.Fatalf("request %d: X-Cache = %q", i, rec.Header().Get("X-Cache"))
+		}
+		if got := rec.Header().Get("X-Request-ID"); got != id {
+			t.Fatalf("request %d: X-Request-ID = %q, want %q", i, got, id)
+		}
+		if got := rec.Header().Values("Content-Encoding"); len(got) != 1 || got[0] != "gzip" {
+			t.Fatalf("request %d: Content-Encoding = %q", i, got)
+		}
+		gz, err := gzip.NewReader(rec.Body)
+		if err != nil {
+			t.Fatalf("request %d: body is not gzip: %v", i, err)
+		}
+		body, err := io.ReadAll(gz)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(body, gzipTestBody) {
+			t.Fatalf("request %d: decompressed body differs from the original", i)
+		}
+	}
+}
+
+func TestResponseCacheSkipsUnwritten(t *testing.T) {
+	cache := NewResponseCache(time.Minute, 1<<20, 0)
+	calls := 0
+	handler := responseCacheMiddleware(cache)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+
+	for i := 0; i < 2; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?root=/tmp", nil))
+	}
+	if calls != 2 {
+		t.Fatalf("handler called %d times, want 2: empty response was cached", calls)
+	}
+}
+
+func TestResponseCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	// Каждая запись занимает 1 байт ключа и 10 байт тела, в кэш помещаются три.
+	cache := NewResponseCache(time.Minute, 1<<20, 35)
+	resp := func() cachedResponse {
+		return cachedResponse{status: http.StatusOK, body: make([]byte, 10), expires: time.Now().Add(time.Minute)}
+	}
+
+	for _, key := range []string{"a", "b", "c"} {
+		cache.put(key, resp())
+	}
+	// Обращение к "a" делает ее свежей, поэтому при переполнении вытесняется "b".
+	if _, ok := cache.get("a"); !ok {
+		t.Fatal("a is missing before overflow")
+	}
+	cache.put("d", resp())
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true, "d": true} {
+		if _, ok := cache.get(key); ok != want {
+			t.Errorf("get(%q) found = %v, want %v", key, ok, want)
+		}
+	}
+	if cache.Len() != 3 || cache.total != 33 {
+		t.Errorf("Len() = %d, total = %d, want 3 and 33", cache.Len(), cache.total)
+	}
+
+	// Замена ответа по тому же ключу не увеличивает общий размер.
+	cache.put("d", resp())
+	if cache.Len() != 3 || cache.total != 33 {
+		t.Errorf("after replace: Len() = %d, total = %d, want 3 and 33", cache.Len(), cache.total)
+	}
+
+	if evicted := cache.EvictHalf(); evicted != 2 || cache.Len() != 1 || cache.total != 11 {
+		t.Errorf("EvictHalf() = %d, Len() = %d, total = %d, want 2, 1 and 11", evicted, cache.Len(), cache.total)
+	}
+}
+
+func TestResponseCacheDropsExpired(t *testing.T) {
+	cache := NewResponseCache(time.Minute, 1<<20, 0)
+	cache.put("a", cachedResponse{status: http.StatusOK, body: []byte("old"), expires: time.Now().Add(-time.Second)})
+
+	if _, ok := cache.get("a"); ok {
+		t.Fatal("expired response was returned")
+	}
+	if cache.Len() != 0 || cache.total != 0 {
+		t.Errorf("expired response is still stored: Len() = %d, total = %d", cache.Len(), cache.total)
+	}
+
+	var disabled *ResponseCache
+	if disabled.Len() != 0 || disabled.EvictHalf() != 0 {
+		t.Error("disabled cache should be empty")
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"container/heap"
+	"io/fs"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	filesystem "filesystem/file_system"
+)
+
+// defaultSearchMaxResults - ограничение --search-max-results по умолчанию.
+const defaultSearchMaxResults = 1000
+
+// SearchResponse - структура ответа поиска по именам файлов.
+type SearchResponse struct {
+	Results   []filesystem.FileInfo `json:"results"`   // Results - найденные элементы, наиболее подходящие первыми.
+	Truncated bool                  `json:"truncated"` // Truncated - найдено больше --search-max-results, выведены не все.
+}
+
+// searchMatcher - функция для проверки имени; возвращает оценку совпадения (меньше - лучше) и признак совпадения.
+type searchMatcher func(name string) (int, bool)
+
+// newSearchMatcher - функция для создания проверки имени по запросу q. Запрос, начинающийся с "~",
+// считается регулярным выражением, остальные ищутся как подстрока без учета регистра.
+// Точное совпадение имени оценивается выше совпадения начала имени, а то - выше подстроки.
+func newSearchMatcher(q string) (searchMatcher, error) {
+	if strings.HasPrefix(q, "~") {
+		re, err := regexp.Compile(q[1:])
+		if err != nil {
+			return nil, err
+		}
+		return func(name string) (int, bool) {
+			loc := re.FindStringIndex(name)
+			switch {
+			case loc == nil:
+				return 0, false
+			case loc[0] == 0 && loc[1] == len(name):
+				return 0, true
+			default:
+				return 1, true
+			}
+		}, nil
+	}
+
+	needle := strings.ToLower(q)
+	return func(name string) (int, bool) {
+		name = strings.ToLower(name)
+		switch {
+		case name == needle:
+			return 0, true
+		case strings.HasPrefix(name, needle):
+			return 1, true
+		case strings.Contains(name, needle):
+			return 2, true
+		default:
+			return 0, false
+		}
+	}, nil
+}
+
+// searchResult - найденный элемент с оценкой совпадения.
+type searchResult struct {
+	file  filesystem.FileInfo
+	score int
+}
+
+// searchBetter - функция для сравнения совпадений: меньшая оценка лучше, при равной оценке
+// выше тот, чей путь меньше.
+func searchBetter(scoreA int, pathA string, scoreB int, pathB string) bool {
+	if scoreA != scoreB {
+		return scoreA < scoreB
+	}
+	return pathA < pathB
+}
+
+// searchHeap - куча совпадений с наименее подходящим в вершине, реализует heap.Interface.
+type searchHeap []searchResult
+
+// Len, Less, Swap и Push - методы heap.Interface.
+func (h searchHeap) Len() int { return len(h) }
+func (h searchHeap) Less(i, j int) bool {
+	return searchBetter(h[j].score, h[j].file.Path, h[i].score, h[i].file.Path)
+}
+func (h searchHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *searchHeap) Push(x interface{}) { *h = append(*h, x.(searchResult)) }
+
+// Pop - метод для извлечения последнего элемента, вызывается из heap.Pop.
+func (h *searchHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+// handleSearch - функция-обработчик для поиска файлов и директорий по имени во всем дереве root.
+// Параметр q - подстрока без учета регистра или, с префиксом "~", регулярное выражение.
+// Размер директорий в результатах не считается, чтобы не обходить дерево повторно.
+func handleSearch(w http.ResponseWriter, r *http.Request) {
+	root, ok := treeRoot(w, r)
+	if !ok {
+		return
+	}
+	q := r.URL.Query().Get("q")
+	if q == "" || q == "~" {
+		writeJSONError(w, http.StatusBadRequest, "не указан запрос q")
+		return
+	}
+	match, err := newSearchMatcher(q)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "неправильное регулярное выражение: "+err.Error())
+		return
+	}
+	binary, err := parseBinary(r, resolveLang(r))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Во время обхода храним только SearchMaxResults лучших совпадений, поэтому при усечении
+	// выводятся наиболее подходящие, а не первые найденные.
+	var best searchHeap
+	truncated := false
+	err = walkTree(r, root, func(path string, d fs.DirEntry) error {
+		score, ok := match(d.Name())
+		if !ok {
+			return nil
+		}
+		if best.Len() < config.SearchMaxResults {
+			heap.Push(&best, searchResult{file: filesystem.EntryInfo(path, d), score: score})
+			return nil
+		}
+		truncated = true
+		if best.Len() == 0 || !searchBetter(score, path, best[0].score, best[0].file.Path) {
+			return nil
+		}
+		best[0] = searchResult{file: filesystem.EntryInfo(path, d), score: score}
+		heap.Fix(&best, 0)
+		return nil
+	})
+	if err != nil {
+		// Клиент отключился или истекло время запроса.
+		return
+	}
+
+	found := []searchResult(best)
+	sort.Slice(found, func(i, j int) bool {
+		return searchBetter(found[i].score, found[i].file.Path, found[j].score, found[j].file.Path)
+	})
+	results := make([]filesystem.FileInfo, len(found))
+	for i := range found {
+		results[i] = found[i].file
+	}
+	convertFileListSizes(results, binary, resolveLang(r))
+
+	writeJSON(w, http.StatusOK, SearchResponse{Results: results, Truncated: truncated})
+}
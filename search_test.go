@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHandleSearchKeepsBestMatches(t *testing.T) {
+	saved := config
+	t.Cleanup(func() { config = saved })
+
+	root := t.TempDir()
+	// Обход идет в лексическом порядке, поэтому худшие совпадения встречаются первыми.
+	for _, name := range []string{"a-report-old.txt", "b-report.txt", "c/report-2024.txt", "d/report"} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		max       int
+		want      []string
+		truncated bool
+	}{
+		{2, []string{"d/report", "c/report-2024.txt"}, true},
+		{3, []string{"d/report", "c/report-2024.txt", "a-report-old.txt"}, true},
+		{10, []string{"d/report", "c/report-2024.txt", "a-report-old.txt", "b-report.txt"}, false},
+		{0, []string{}, true},
+	}
+	for _, tt := range tests {
+		config.SearchMaxResults = tt.max
+		rec := httptest.NewRecorder()
+		handleSearch(rec, httptest.NewRequest(http.MethodGet, "/api/search?"+url.Values{"root": {root}, "q": {"report"}}.Encode(), nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("max %d: status = %d, body %s", tt.max, rec.Code, rec.Body)
+		}
+
+		var resp SearchResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Truncated != tt.truncated {
+			t.Errorf("max %d: truncated = %v, want %v", tt.max, resp.Truncated, tt.truncated)
+		}
+		got := make([]string, len(resp.Results))
+		for i, file := range resp.Results {
+			got[i], _ = filepath.Rel(root, file.Path)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("max %d: results = %q, want %q", tt.max, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("max %d: results = %q, want %q", tt.max, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Параметры контроля времени ответа.
+const (
+	sloWindow     = 60 * time.Second // sloWindow - окно, за которое считается доля нарушений.
+	sloMinSamples = 10               // sloMinSamples - минимум запросов в окне для перехода в режим деградации.
+)
+
+// degradedReasonSLO - причина перехода в режим деградации из-за превышения времени ответа.
+const degradedReasonSLO = "slo_violation"
+
+// sloSample - один замер времени ответа.
+type sloSample struct {
+	at       time.Time
+	violated bool
+}
+
+// SLOMonitor - структура для контроля времени ответа. Если доля медленных ответов за окно превышает
+// maxRate, сервер переходит в режим деградации и отдает только результаты из кэша.
+type SLOMonitor struct {
+	mu            sync.Mutex
+	maxDuration   time.Duration // maxDuration - допустимое время ответа.
+	maxRate       float64       // maxRate - допустимая доля нарушений в окне.
+	samples       []sloSample   // samples - замеры за последние sloWindow.
+	degraded      bool          // degraded - включен режим деградации.
+	degradedSince time.Time     // degradedSince - время включения режима деградации.
+}
+
+// slo - контроль времени ответа, nil если --slo-max-ms не задан.
+var slo *SLOMonitor
+
+// NewSLOMonitor - функция для создания контроля времени ответа.
+func NewSLOMonitor(maxDuration time.Duration, maxRate float64) *SLOMonitor {
+	return &SLOMonitor{maxDuration: maxDuration, maxRate: maxRate}
+}
+
+// Observe - метод для учета времени ответа. Включает режим деградации при частых нарушениях
+// и выключает его, если после окна в режиме деградации нарушений стало вдвое меньше допустимого.
+func (m *SLOMonitor) Observe(path string, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	violated := d > m.maxDuration
+	if violated {
+		sloViolationsTotal.Inc()
+		config.Logger.Info(fmt.Sprintf("Предупреждение: время ответа %s для %s превысило %s", d, path, m.maxDuration))
+	}
+
+	// Оставляем только замеры за последнее окно.
+	m.samples = append(m.samples, sloSample{at: now, violated: violated})
+	first := 0
+	for first < len(m.samples) && now.Sub(m.samples[first].at) > sloWindow {
+		first++
+	}
+	m.samples = m.samples[first:]
+
+	violations := 0
+	for _, sample := range m.samples {
+		if sample.violated {
+			violations++
+		}
+	}
+	rate := float64(violations) / float64(len(m.samples))
+
+	switch {
+	case !m.degraded && len(m.samples) >= sloMinSamples && rate > m.maxRate:
+		m.degraded = true
+		m.degradedSince = now
+		config.Logger.Info(fmt.Sprintf("Включен режим деградации: %.0f%% ответов медленнее %s, выдаются только результаты из кэша", rate*100, m.maxDuration))
+	case m.degraded && now.Sub(m.degradedSince) > sloWindow && rate <= m.maxRate/2:
+		m.degraded = false
+		config.Logger.Info("Режим деградации выключен: время ответа в норме")
+	}
+}
+
+// Degraded - метод для проверки, включен ли режим деградации.
+func (m *SLOMonitor) Degraded() bool {
+	if m == nil {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.degraded
+}
+
+// Reset - метод для ручного выключения режима деградации.
+func (m *SLOMonitor) Reset() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.degraded = false
+	m.samples = nil
+}
+
+// handleResetDegraded - функция-обработчик для ручного выключения режима деградации.
+func handleResetDegraded(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSONError(w, http.StatusMethodNotAllowed, "метод не поддерживается")
+		return
+	}
+	slo.Reset()
+	config.Logger.Info("Режим деградации выключен вручную")
+	writeJSON(w, http.StatusOK, map[string]bool{"degraded": false})
+}
+
+// writeDegradedUnavailable - функция для ответа в режиме деградации, если результата нет в кэше.
+func writeDegradedUnavailable(w http.ResponseWriter, format, lang string) {
+	w.Header().Set("Retry-After", "60")
+	if format == formatJSON {
+		writeJSON(w, http.StatusServiceUnavailable, DegradedResponse{
+			Error:    translate(lang, "error.degraded"),
+			Degraded: true,
+			Reason:   degradedReasonSLO,
+		})
+		return
+	}
+	http.Error(w, translate(lang, "error.degraded"), http.StatusServiceUnavailable)
+}
+
+// DegradedResponse - структура ответа JSON в режиме деградации при отсутствии результата в кэше.
+type DegradedResponse struct {
+	Error    string `json:"error"`    // Error - текст ошибки.
+	Degraded bool   `json:"degraded"` // Degraded - включен режим деградации.
+	Reason   string `json:"reason"`   // Reason - причина перехода в режим деградации.
+}
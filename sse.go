@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	filesystem "filesystem/file_system"
+)
+
+// SizeProgressEvent - структура события о ходе вычисления размера директории.
+type SizeProgressEvent struct {
+	Bytes int64 `json:"bytes"` // Bytes - размер, подсчитанный к текущему моменту.
+	Done  bool  `json:"done"`  // Done - признак завершения подсчета.
+}
+
+// writeSSE - вспомогательная функция для отправки события Server-Sent Events.
+func writeSSE(w http.ResponseWriter, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	return err
+}
+
+// handleSizeStream - функция-обработчик для потоковой передачи хода вычисления размера директории.
+func handleSizeStream(w http.ResponseWriter, r *http.Request) {
+	dirPath := r.URL.Query().Get("root")
+	if dirPath == "" {
+		writeJSONError(w, http.StatusBadRequest, "не указана директория(root)")
+		return
+	}
+	if err := checkPath(dirPath, resolveLang(r)); err != nil {
+		writeJSONError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSONError(w, http.StatusInternalServerError, "потоковая передача не поддерживается")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	ctx := r.Context()
+	progress := make(chan int64, 1)
+	done := make(chan float64, 1)
+	go func() {
+		done <- filesystem.GetDirSizeWithProgress(ctx, dirPath, config.requestScanOptions(r), progress)
+	}()
+
+	for {
+		select {
+		case bytes := <-progress:
+			if err := writeSSE(w, "progress", SizeProgressEvent{Bytes: bytes}); err != nil {
+				config.Logger.Error("Ошибка при отправке события:", err)
+				return
+			}
+			flusher.Flush()
+		case size := <-done:
+			if err := writeSSE(w, "done", SizeProgressEvent{Bytes: int64(size), Done: true}); err != nil {
+				config.Logger.Error("Ошибка при отправке события:", err)
+			}
+			flusher.Flush()
+			return
+		case <-ctx.Done():
+			// Клиент отключился, обход прервется по отмене контекста.
+			return
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// recentPathsLimit - количество последних просмотренных путей, выводимых на главной странице.
+const recentPathsLimit = 10
+
+// ServerStats - структура для накопления статистики работы сервера с момента запуска.
+type ServerStats struct {
+	startTime time.Time
+	scans     int64 // scans - количество выполненных сканирований.
+
+	mu           sync.Mutex
+	recentPaths  []string  // recentPaths - последние просмотренные пути, новые в начале.
+	webhookOK    bool      // webhookOK - результат последней отправки статистики.
+	webhookLast  time.Time // webhookLast - время последней отправки статистики.
+	webhookError string    // webhookError - текст последней ошибки отправки статистики.
+}
+
+// stats - статистика работы сервера.
+var stats = &ServerStats{startTime: time.Now()}
+
+// RecordScan - метод для учета выполненного сканирования пути.
+func (s *ServerStats) RecordScan(path string) {
+	atomic.AddInt64(&s.scans, 1)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paths := []string{path}
+	for _, recent := range s.recentPaths {
+		if recent != path && len(paths) < recentPathsLimit {
+			paths = append(paths, recent)
+		}
+	}
+	s.recentPaths = paths
+}
+
+// RecordWebhook - метод для учета результата отправки статистики на внешний сервер.
+func (s *ServerStats) RecordWebhook(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.webhookLast = time.Now()
+	s.webhookOK = err == nil
+	s.webhookError = ""
+	if err != nil {
+		s.webhookError = err.Error()
+	}
+}
+
+// DashboardData - структура с данными для главной страницы.
+type DashboardData struct {
+	Uptime       string   // Uptime - время работы сервера.
+	Scans        int64    // Scans - количество выполненных сканирований.
+	CacheHitRate string   // CacheHitRate - доля ответов из кэша, пустая если кэш ответов отключен.
+	WebhookState string   // WebhookState - состояние отправки статистики: "ok", "error" или "unknown".
+	WebhookError string   // WebhookError - текст последней ошибки отправки статистики.
+	RecentPaths  []string // RecentPaths - последние просмотренные пути.
+	PrewarmPaths []string // PrewarmPaths - пути, прогреваемые при запуске.
+}
+
+// Dashboard - метод для формирования данных главной страницы.
+func (s *ServerStats) Dashboard() DashboardData {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data := DashboardData{
+		Uptime:       time.Since(s.startTime).Round(time.Second).String(),
+		Scans:        atomic.LoadInt64(&s.scans),
+		WebhookState: "unknown",
+		WebhookError: s.webhookError,
+		RecentPaths:  append([]string(nil), s.recentPaths...),
+		PrewarmPaths: config.PrewarmPaths,
+	}
+	if responseCache != nil {
+		data.CacheHitRate = fmt.Sprintf("%.1f%%", responseCache.HitRate()*100)
+	}
+	if !s.webhookLast.IsZero() {
+		data.WebhookState = "error"
+		if s.webhookOK {
+			data.WebhookState = "ok"
+		}
+	}
+	return data
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	filesystem "filesystem/file_system"
+)
+
+// StreamRow - структура с данными одной строки таблицы в потоковом режиме.
+type StreamRow struct {
+	File     filesystem.FileInfo // File - элемент директории.
+	Messages map[string]string   // Messages - каталог сообщений интерфейса.
+}
+
+// handleFileSystemStream - функция-обработчик потокового режима: страница отправляется сразу,
+// а строки таблицы дописываются по мере готовности элементов. Сортировка в этом режиме не применяется.
+func handleFileSystemStream(w http.ResponseWriter, r *http.Request, dirPath string, opts filesystem.Options, binary bool, lang string) {
+	startTime := time.Now()
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "потоковая передача не поддерживается", http.StatusInternalServerError)
+		return
+	}
+
+	tmpl, err := streamTemplate.Get()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("ошибка загрузки шаблона: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	data := PageData{LastPath: dirPath, Lang: lang, Messages: catalogs[lang]}
+	w.Header().Set("Content-Type", "text/html")
+	if err := tmpl.ExecuteTemplate(w, "stream_head", data); err != nil {
+		config.Logger.Error("Ошибка при рендеринге шаблона:", err)
+		return
+	}
+	flusher.Flush()
+
+	// Сканируем в отдельной горутине и выводим строки по мере поступления.
+	rows := make(chan filesystem.FileInfo)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- filesystem.StreamDirByReadDir(r.Context(), dirPath, opts, rows)
+	}()
+
+	for fileInfo := range rows {
+		var unit string
+		fileInfo.Size, unit = filesystem.ConvertSize(fileInfo.Size, binary)
+		fileInfo.Unit = translate(lang, unit)
+		if err := tmpl.ExecuteTemplate(w, "stream_row", StreamRow{File: fileInfo, Messages: data.Messages}); err != nil {
+			config.Logger.Error("Ошибка при рендеринге шаблона:", err)
+		}
+		flusher.Flush()
+	}
+
+	if err := <-errCh; err != nil {
+		data.ErrorMsg = fmt.Sprintf(translate(lang, "error.dir_read"), err)
+	} else {
+		stats.RecordScan(dirPath)
+	}
+	elapsed := time.Since(startTime)
+	data.EndTime = formatElapsed(elapsed)
+	data.EndRaw = elapsed.String()
+	if err := tmpl.ExecuteTemplate(w, "stream_foot", data); err != nil {
+		config.Logger.Error("Ошибка при рендеринге шаблона:", err)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tlsEnabled - функция для проверки, нужно ли принимать соединения по HTTPS.
+// Сертификат и ключ задаются только вместе.
+func (cfg Config) tlsEnabled() (bool, error) {
+	if (cfg.TLSCert == "") != (cfg.TLSKey == "") {
+		return false, errors.New("флаги --tls-cert и --tls-key задаются только вместе")
+	}
+	return cfg.TLSCert != "" || cfg.TLSAuto, nil
+}
+
+// selfSignedCertificate - функция для создания самоподписанного сертификата на localhost
+// для локальной разработки по HTTPS. Сертификат живет только в памяти и действует год.
+func selfSignedCertificate() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("ошибка создания ключа: %v", err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("ошибка создания серийного номера: %v", err)
+	}
+
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{Organization: []string{"filesystem (self-signed)"}},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(365 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("ошибка создания сертификата: %v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
+
+// httpsRedirectHandler - функция для создания обработчика, перенаправляющего запросы (301) на тот же
+// адрес по HTTPS на порт httpsPort. Для стандартного порта 443 он в адресе не указывается.
+func httpsRedirectHandler(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+		if httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		} else if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+}
+
+// startHTTPRedirectServer - функция для запуска в фоне сервера на порту port, перенаправляющего
+// все запросы на HTTPS-сервер с портом httpsPort.
+func startHTTPRedirectServer(port int, httpsPort string) {
+	addr := ":" + strconv.Itoa(port)
+	go func() {
+		config.Logger.Info("Перенаправление HTTP на HTTPS запущено на", addr)
+		if err := http.ListenAndServe(addr, httpsRedirectHandler(httpsPort)); err != nil {
+			config.Logger.Fatal(fmt.Sprintf("Ошибка при запуске сервера перенаправления: %v", err))
+		}
+	}()
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSecurityHeadersHSTS(t *testing.T) {
+	headers := SecurityHeaders{
+		ContentTypeOptions:      "nosniff",
+		StrictTransportSecurity: "max-age=31536000; includeSubDomains",
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		tlsEnabled bool
+		want       string
+	}{
+		{true, "max-age=31536000; includeSubDomains"},
+		{false, ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		securityHeadersMiddleware(headers, tt.tlsEnabled)(next).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if got := rec.Header().Get("Strict-Transport-Security"); got != tt.want {
+			t.Errorf("tls=%v: Strict-Transport-Security = %q, want %q", tt.tlsEnabled, got, tt.want)
+		}
+		if got := rec.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+			t.Errorf("tls=%v: X-Content-Type-Options = %q", tt.tlsEnabled, got)
+		}
+	}
+}
+
+func TestHTTPSRedirectHandler(t *testing.T) {
+	tests := []struct {
+		httpsPort string
+		target    string
+		host      string
+		want      string
+	}{
+		{"9443", "/?root=/tmp&sort=asc", "example.com:8080", "https://example.com:9443/?root=/tmp&sort=asc"},
+		{"9443", "/api/files", "example.com", "https://example.com:9443/api/files"},
+		{"443", "/healthz", "example.com:80", "https://example.com/healthz"},
+		{"443", "/", "[::1]:80", "https://[::1]/"},
+		{"8443", "/", "[::1]:80", "https://[::1]:8443/"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		req.Host = tt.host
+		rec := httptest.NewRecorder()
+		httpsRedirectHandler(tt.httpsPort).ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusMovedPermanently {
+			t.Errorf("%s%s: status = %d, want %d", tt.host, tt.target, rec.Code, http.StatusMovedPermanently)
+		}
+		if got := rec.Header().Get("Location"); got != tt.want {
+			t.Errorf("%s%s: Location = %q, want %q", tt.host, tt.target, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"container/heap"
+	"io/fs"
+	"net/http"
+	"sort"
+	"strconv"
+
+	filesystem "filesystem/file_system"
+)
+
+// Ограничения количества файлов в /api/top.
+const (
+	defaultTopN = 20   // defaultTopN - количество файлов, если n не указан.
+	maxTopN     = 1000 // maxTopN - наибольшее допустимое n.
+)
+
+// fileHeap - куча файлов с наименьшим размером в вершине, реализует heap.Interface.
+type fileHeap []filesystem.FileInfo
+
+// Len, Less, Swap и Push - методы heap.Interface.
+func (h fileHeap) Len() int            { return len(h) }
+func (h fileHeap) Less(i, j int) bool  { return h[i].Size < h[j].Size }
+func (h fileHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *fileHeap) Push(x interface{}) { *h = append(*h, x.(filesystem.FileInfo)) }
+
+// Pop - метод для извлечения последнего элемента, вызывается из heap.Pop.
+func (h *fileHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+// handleTop - функция-обработчик для вывода n самых больших файлов во всем дереве root по убыванию
+// размера. Во время обхода хранится только куча из n файлов, поэтому память не зависит от размера дерева.
+func handleTop(w http.ResponseWriter, r *http.Request) {
+	root, ok := treeRoot(w, r)
+	if !ok {
+		return
+	}
+	n := defaultTopN
+	if value := r.URL.Query().Get("n"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxTopN {
+			writeJSONError(w, http.StatusBadRequest, "n должен быть числом от 1 до "+strconv.Itoa(maxTopN))
+			return
+		}
+		n = parsed
+	}
+	binary, err := parseBinary(r, resolveLang(r))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	largest := make(fileHeap, 0, n)
+	err = walkTree(r, root, func(path string, d fs.DirEntry) error {
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		// Файл больше самого маленького из найденных заменяет его в куче.
+		if largest.Len() == n && float64(info.Size()) <= largest[0].Size {
+			return nil
+		}
+		file := filesystem.EntryInfo(path, d)
+		if largest.Len() < n {
+			heap.Push(&largest, file)
+		} else {
+			largest[0] = file
+			heap.Fix(&largest, 0)
+		}
+		return nil
+	})
+	if err != nil {
+		// Клиент отключился или истекло время запроса.
+		return
+	}
+
+	files := []filesystem.FileInfo(largest)
+	sort.Slice(files, func(i, j int) bool { return files[i].Size > files[j].Size })
+	convertFileListSizes(files, binary, resolveLang(r))
+	writeJSON(w, http.StatusOK, files)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	filesystem "filesystem/file_system"
+)
+
+// defaultTreeTextDepth - количество уровней текстового дерева, если depth не указан.
+const defaultTreeTextDepth = 3
+
+// handleTreeText - функция-обработчик для вывода дерева директории в текстовом виде, как команда tree(1).
+// Без параметра depth выводится defaultTreeTextDepth уровней, depth=0 снимает ограничение.
+func handleTreeText(w http.ResponseWriter, r *http.Request) {
+	dirPath := r.URL.Query().Get("root")
+	if dirPath == "" {
+		http.Error(w, "не указана директория(root)", http.StatusBadRequest)
+		return
+	}
+	if err := checkPath(dirPath, resolveLang(r)); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	depth := defaultTreeTextDepth
+	if value := r.URL.Query().Get("depth"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			http.Error(w, "depth должен быть неотрицательным числом", http.StatusBadRequest)
+			return
+		}
+		depth = parsed
+	}
+
+	// Дерево отправляется по мере обхода. Код ошибки можно вернуть, только пока ничего не записано.
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	recorder := &responseRecorder{ResponseWriter: w}
+	err := filesystem.WriteTree(r.Context(), recorder, dirPath, depth, config.requestScanOptions(r))
+	switch {
+	case err == nil:
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		// Клиент отключился или истекло время запроса - ответ отправит timeoutMiddleware, если он нужен.
+	case recorder.status == 0:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		requestLogger(r).Error("ошибка вывода дерева:", err)
+	}
+}
+
+// handleTree - функция-обработчик для вывода дерева директории в JSON: у каждого узла есть name, path,
+// isDir, size и, для директорий в пределах depth, children. Подходит для построения диаграмм вроде d3.js.
+func handleTree(w http.ResponseWriter, r *http.Request) {
+	dirPath := r.URL.Query().Get("root")
+	if dirPath == "" {
+		writeJSONError(w, http.StatusBadRequest, "не указана директория(root)")
+		return
+	}
+	if err := checkPath(dirPath, resolveLang(r)); err != nil {
+		writeJSONError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	depth := 0
+	if value := r.URL.Query().Get("depth"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			writeJSONError(w, http.StatusBadRequest, "depth должен быть неотрицательным числом")
+			return
+		}
+		depth = parsed
+	}
+
+	tree, err := filesystem.BuildTree(r.Context(), dirPath, depth, config.requestScanOptions(r))
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		// Клиент отключился или истекло время запроса - ответ отправит timeoutMiddleware, если он нужен.
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, tree)
+}
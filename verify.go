@@ -0,0 +1,69 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// VerifyResponse - структура ответа проверки контрольной суммы файла.
+type VerifyResponse struct {
+	Match    bool   `json:"match"`              // Match - совпадает ли вычисленная сумма с ожидаемой.
+	Computed string `json:"computed,omitempty"` // Computed - вычисленная сумма SHA-256, выводится только при совпадении.
+	Expected string `json:"expected,omitempty"` // Expected - ожидаемая сумма SHA-256.
+	Path     string `json:"path,omitempty"`     // Path - путь к проверенному файлу.
+}
+
+// handleVerify - функция-обработчик для проверки контрольной суммы SHA-256 файла.
+// При несовпадении вычисленная сумма не раскрывается.
+func handleVerify(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeJSONError(w, http.StatusMethodNotAllowed, "метод не поддерживается")
+		return
+	}
+
+	filePath := r.URL.Query().Get("path")
+	expected := strings.ToLower(r.URL.Query().Get("sha256"))
+	if filePath == "" || expected == "" {
+		writeJSONError(w, http.StatusBadRequest, "не указаны path и sha256")
+		return
+	}
+	if _, err := hex.DecodeString(expected); err != nil || len(expected) != sha256.Size*2 {
+		writeJSONError(w, http.StatusBadRequest, "sha256 должен содержать 64 шестнадцатеричных символа")
+		return
+	}
+	if err := checkPath(filePath, resolveLang(r)); err != nil {
+		writeJSONError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		writeJSONError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	defer file.Close()
+
+	if info, err := file.Stat(); err == nil && info.IsDir() {
+		writeJSONError(w, http.StatusBadRequest, "path указывает на директорию")
+		return
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	computed := hex.EncodeToString(hash.Sum(nil))
+
+	if subtle.ConstantTimeCompare([]byte(computed), []byte(expected)) != 1 {
+		writeJSON(w, http.StatusOK, VerifyResponse{Match: false})
+		return
+	}
+	writeJSON(w, http.StatusOK, VerifyResponse{Match: true, Computed: computed, Expected: expected, Path: filePath})
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// walkTree - функция для обхода всего дерева root по запросу r. Символические ссылки не
+// разыменовываются, недоступные элементы пропускаются, а поддиректории, закрытые --forbidden,
+// не обходятся. Обход прерывается с ошибкой контекста при отключении клиента или истечении
+// времени запроса. fn вызывается для каждого элемента, кроме самого root.
+func walkTree(r *http.Request, root string, fn func(path string, d fs.DirEntry) error) error {
+	lang := resolveLang(r)
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() && path != root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if err := r.Context().Err(); err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+		if d.IsDir() && checkPath(path, lang) != nil {
+			return filepath.SkipDir
+		}
+		return fn(path, d)
+	})
+}
+
+// treeRoot - функция для получения директории из параметра root запроса к обработчикам, обходящим
+// все дерево. При ошибке ответ уже отправлен и возвращается false.
+func treeRoot(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if r.URL.Query().Get("root") == "" {
+		writeJSONError(w, http.StatusBadRequest, "не указан root")
+		return "", false
+	}
+	root := filepath.Clean(r.URL.Query().Get("root"))
+	if err := checkPath(root, resolveLang(r)); err != nil {
+		writeJSONError(w, http.StatusForbidden, err.Error())
+		return "", false
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		writeJSONError(w, http.StatusNotFound, err.Error())
+		return "", false
+	}
+	if !info.IsDir() {
+		writeJSONError(w, http.StatusBadRequest, "root указывает не на директорию")
+		return "", false
+	}
+	return root, true
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	filesystem "filesystem/file_system"
+)
+
+// WatchConfig - структура с настройками наблюдения за размером директории.
+type WatchConfig struct {
+	Path         string       `json:"path"`         // Path - наблюдаемая директория.
+	MaxGrowthPct float64      `json:"maxGrowthPct"` // MaxGrowthPct - допустимый рост размера за интервал в процентах.
+	Interval     jsonDuration `json:"interval"`     // Interval - период измерения размера.
+}
+
+// jsonDuration - длительность, записываемая в JSON строкой вида "1h".
+type jsonDuration time.Duration
+
+// MarshalJSON - метод для вывода длительности строкой.
+func (d jsonDuration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON - метод для чтения длительности из строки.
+func (d *jsonDuration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = jsonDuration(parsed)
+	return nil
+}
+
+// parseWatchSizes - функция для разбора значения --watch-sizes: один объект или массив объектов JSON.
+func parseWatchSizes(value string) ([]WatchConfig, error) {
+	var watches []WatchConfig
+	if err := json.Unmarshal([]byte(value), &watches); err != nil {
+		var watch WatchConfig
+		if err := json.Unmarshal([]byte(value), &watch); err != nil {
+			return nil, fmt.Errorf("неправильно указано наблюдение %q: %v", value, err)
+		}
+		watches = []WatchConfig{watch}
+	}
+	for _, watch := range watches {
+		if watch.Path == "" || watch.Interval <= 0 {
+			return nil, fmt.Errorf("для наблюдения нужно указать path и положительный interval: %q", value)
+		}
+	}
+	return watches, nil
+}
+
+// WatchState - структура с результатами последнего измерения наблюдаемой директории.
+type WatchState struct {
+	Path         string  `json:"path"`         // Path - наблюдаемая директория.
+	CurrentSize  float64 `json:"currentSize"`  // CurrentSize - размер при последнем измерении.
+	PreviousSize float64 `json:"previousSize"` // PreviousSize - размер при предыдущем измерении.
+	GrowthPct    float64 `json:"growthPct"`    // GrowthPct - рост размера между измерениями в процентах.
+	AlertFired   bool    `json:"alertFired"`   // AlertFired - рост превысил допустимый при последнем измерении.
+}
+
+// watchStates - результаты наблюдения в порядке настроек.
+var (
+	watchMu     sync.Mutex
+	watchStates []WatchState
+)
+
+// startWatches - функция для запуска фонового наблюдения за размером директорий.
+func startWatches(ctx context.Context, watches []WatchConfig) {
+	watchMu.Lock()
+	watchStates = make([]WatchState, len(watches))
+	for i, watch := range watches {
+		watchStates[i].Path = watch.Path
+	}
+	watchMu.Unlock()
+
+	for i, watch := range watches {
+		go watchSize(ctx, i, watch)
+	}
+}
+
+// watchSize - функция для периодического измерения размера директории и сравнения с предыдущим.
+func watchSize(ctx context.Context, index int, watch WatchConfig) {
+	ticker := time.NewTicker(time.Duration(watch.Interval))
+	defer ticker.Stop()
+
+	measured := false
+	for {
+		size := filesystem.GetDirSize(ctx, watch.Path, config.scanOptions())
+
+		watchMu.Lock()
+		state := &watchStates[index]
+		state.PreviousSize = state.CurrentSize
+		state.CurrentSize = size
+		state.GrowthPct = 0
+		if measured && state.PreviousSize > 0 {
+			state.GrowthPct = (size - state.PreviousSize) / state.PreviousSize * 100
+		}
+		state.AlertFired = measured && state.GrowthPct > watch.MaxGrowthPct
+		if state.AlertFired {
+			config.Logger.Info(fmt.Sprintf("ПРЕДУПРЕЖДЕНИЕ: размер %s вырос на %.1f%% (допустимо %.1f%%): %.0f -> %.0f байт",
+				watch.Path, state.GrowthPct, watch.MaxGrowthPct, state.PreviousSize, size))
+		}
+		watchMu.Unlock()
+		measured = true
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// handleWatches - функция-обработчик для вывода состояния наблюдения за размером директорий.
+func handleWatches(w http.ResponseWriter, r *http.Request) {
+	watchMu.Lock()
+	states := make([]WatchState, len(watchStates))
+	copy(states, watchStates)
+	watchMu.Unlock()
+
+	writeJSON(w, http.StatusOK, states)
+}
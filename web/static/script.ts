@@ -24,6 +24,49 @@ function bindSortSelect() {
             console.log('Sort type saved:', sortSelect.value); // Отладка
         });
     }
+
+    const bySelect = document.getElementById('by') as HTMLSelectElement | null;
+    if (bySelect) {
+        const savedSortKey = localStorage.getItem('sortKey');
+        if (savedSortKey) {
+            bySelect.value = savedSortKey;
+        }
+
+        bySelect.addEventListener('change', function () {
+            localStorage.setItem('sortKey', bySelect.value);
+        });
+    }
+
+    const dirsFirstCheckbox = document.getElementById('dirsFirst') as HTMLInputElement | null;
+    if (dirsFirstCheckbox) {
+        dirsFirstCheckbox.checked = dirsFirstCheckbox.checked || localStorage.getItem('dirsFirst') === 'true';
+        dirsFirstCheckbox.addEventListener('change', function () {
+            localStorage.setItem('dirsFirst', String(dirsFirstCheckbox.checked));
+        });
+    }
+}
+
+// Функция для привязки кнопки закрытия объявления. Закрытое объявление не выводится
+// до конца сессии, пока администратор не сменит его текст.
+function bindBanner() {
+    const banner = document.getElementById('banner') as HTMLElement | null;
+    if (!banner) {
+        return;
+    }
+    const text = banner.textContent?.trim() || '';
+    if (sessionStorage.getItem('bannerDismissed') === text) {
+        banner.remove();
+        return;
+    }
+    banner.querySelector('.banner__close')?.addEventListener('click', function () {
+        sessionStorage.setItem('bannerDismissed', text);
+        banner.remove();
+    });
+}
+
+// Функция для получения параметров сортировки из сохраненных значений
+function sortParams(sortType: string, sortKey: string, dirsFirst: string = localStorage.getItem('dirsFirst') || 'false'): string {
+    return '&sort=' + encodeURIComponent(sortType) + '&by=' + encodeURIComponent(sortKey) + '&dirsFirst=' + encodeURIComponent(dirsFirst);
 }
 
 // Функция для отправки формы
@@ -40,6 +83,7 @@ document.getElementById('directoryForm')?.addEventListener('submit', function (e
           bindStatButton();
           bindNavigationLinks();
           bindBackButton();
+          bindRefreshButton();
           bindSortSelect(); // Восстанавливаем выбор сортировки
           history.pushState(null, '', '/');
       }).finally(() => {
@@ -53,7 +97,7 @@ function bindNavigationLinks() {
     links.forEach(link => {
         link.addEventListener('click', function (event: Event) {
             event.preventDefault();
-            const path = (event.target as HTMLElement).getAttribute('data-path');
+            const path = (event.currentTarget as HTMLElement).getAttribute('data-path');
             if (path) {
                 navigateTo(path);
             }
@@ -69,12 +113,42 @@ function bindBackButton() {
     }
 }
 
+// Функция для привязки кнопки повторного сканирования
+function bindRefreshButton() {
+    const refreshButton = document.querySelector('.button__refresh') as HTMLElement | null;
+    if (refreshButton) {
+        refreshButton.addEventListener('click', function () {
+            const path = refreshButton.getAttribute('data-path');
+            const sortType = refreshButton.getAttribute('data-sort') || 'asc';
+            const sortKey = refreshButton.getAttribute('data-by') || 'size';
+            if (path) {
+                showLoader();
+                fetch('/?root=' + encodeURIComponent(path) + sortParams(sortType, sortKey, refreshButton.getAttribute('data-dirs-first') || 'false') + '&refresh=1', {
+                    method: 'GET'
+                }).then(response => response.text())
+                  .then(html => {
+                      document.body.innerHTML = html;
+                      bindStatButton();
+                      bindNavigationLinks();
+                      bindBackButton();
+                      bindRefreshButton();
+                      bindSortSelect(); // Восстанавливаем выбор сортировки
+                      history.pushState(null, '', '/');
+                  }).finally(() => {
+                      hideLoader();
+                  });
+            }
+        });
+    }
+}
+
 // Функция для навигации по пути
 function navigateTo(path: string): void {
     const sortType = localStorage.getItem('sortType') || 'asc'; // Используем сохраненное значение или значение по умолчанию
+    const sortKey = localStorage.getItem('sortKey') || 'size';
     console.log('Navigating to:', path, 'with sort type:', sortType); // Отладка
     showLoader();
-    fetch('/?root=' + encodeURIComponent(path) + '&sort=' + encodeURIComponent(sortType), {
+    fetch('/?root=' + encodeURIComponent(path) + sortParams(sortType, sortKey), {
         method: 'GET'
     }).then(response => response.text())
       .then(html => {
@@ -82,6 +156,7 @@ function navigateTo(path: string): void {
           bindStatButton();
           bindNavigationLinks();
           bindBackButton();
+          bindRefreshButton();
           bindSortSelect(); // Восстанавливаем выбор сортировки
           history.pushState(null, '', '/');
       }).finally(() => {
@@ -91,13 +166,14 @@ function navigateTo(path: string): void {
 
 // Функция для возврата на предыдущую директорию
 function goBack(): void {
-    const currentPath = document.querySelector('p')?.innerText.split(': ')[1];
+    const currentPath = document.getElementById('currentPath')?.getAttribute('data-path');
     if (currentPath) {
         const parentPath = currentPath.split('/').slice(0, -1).join('/');
         const sortType = localStorage.getItem('sortType') || 'asc'; // Используем сохраненное значение или значение по умолчанию
+        const sortKey = localStorage.getItem('sortKey') || 'size';
         console.log('Going back to:', parentPath, 'with sort type:', sortType); // Отладка
         showLoader();
-        fetch('/?root=' + encodeURIComponent(parentPath) + '&sort=' + encodeURIComponent(sortType), {
+        fetch('/?root=' + encodeURIComponent(parentPath) + sortParams(sortType, sortKey), {
             method: 'GET'
         }).then(response => response.text())
           .then(html => {
@@ -105,6 +181,7 @@ function goBack(): void {
               bindStatButton();
               bindNavigationLinks();
               bindBackButton();
+              bindRefreshButton();
               bindSortSelect(); // Восстанавливаем выбор сортировки
               history.pushState(null, '', '/');
           }).finally(() => {
@@ -139,10 +216,26 @@ function hideLoader(): void {
     }
 }
 
+// Функция для автопрокрутки страницы, пока строки таблицы поступают в потоковом режиме
+function watchStreamingTable(): void {
+    if (!document.documentElement.classList.contains('streaming')) {
+        return;
+    }
+    const observer = new MutationObserver(() => {
+        window.scrollTo(0, document.documentElement.scrollHeight);
+    });
+    observer.observe(document.documentElement, { childList: true, subtree: true });
+    document.addEventListener('DOMContentLoaded', () => observer.disconnect());
+}
+
+watchStreamingTable();
+
 // Инициализация обработчиков при загрузке страницы
 document.addEventListener('DOMContentLoaded', function () {
     bindStatButton();
     bindNavigationLinks();
     bindBackButton();
+    bindRefreshButton();
     bindSortSelect(); // Инициализация обработчика изменения сортировки
+    bindBanner();
 });
\ No newline at end of file
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Параметры отправки статистики.
+const (
+	webhookAttempts     = 3                      // webhookAttempts - количество попыток отправки.
+	webhookRetryDelay   = 500 * time.Millisecond // webhookRetryDelay - пауза перед повтором, растет с каждой попыткой.
+	webhookTimeout      = 10 * time.Second       // webhookTimeout - время ожидания ответа сервера статистики.
+	breakerMaxFailures  = 5                      // breakerMaxFailures - ошибки подряд, после которых отправка приостанавливается.
+	breakerOpenDuration = 30 * time.Second       // breakerOpenDuration - время, на которое приостанавливается отправка.
+)
+
+// Состояния автоматического выключателя.
+const (
+	breakerClosed   = iota // breakerClosed - запросы отправляются.
+	breakerOpen            // breakerOpen - запросы не отправляются.
+	breakerHalfOpen        // breakerHalfOpen - отправляется один пробный запрос.
+)
+
+// circuitBreaker - автоматический выключатель: после серии ошибок временно перестает пропускать запросы.
+type circuitBreaker struct {
+	mu          sync.Mutex
+	state       int
+	failures    int           // failures - количество ошибок подряд.
+	openedAt    time.Time     // openedAt - время перехода в открытое состояние.
+	maxFailures int           // maxFailures - ошибки подряд, после которых выключатель открывается.
+	openFor     time.Duration // openFor - время, через которое разрешается пробный запрос.
+}
+
+// Allow - метод для проверки, можно ли отправить запрос. В полуоткрытом состоянии
+// пропускается только один пробный запрос до получения его результата.
+func (b *circuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.openFor {
+			return false
+		}
+		b.state = breakerHalfOpen
+		return true
+	case breakerHalfOpen:
+		return false
+	}
+	return true
+}
+
+// Record - метод для учета результата запроса.
+func (b *circuitBreaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.maxFailures {
+		if b.state != breakerOpen {
+			config.Logger.Info(fmt.Sprintf("Отправка статистики приостановлена на %s после %d ошибок подряд", b.openFor, b.failures))
+		}
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// webhookBreaker - выключатель для отправки статистики.
+var webhookBreaker = &circuitBreaker{maxFailures: breakerMaxFailures, openFor: breakerOpenDuration}
+
+// webhookClient - HTTP-клиент для отправки статистики с ограничением времени ожидания.
+var webhookClient = &http.Client{Timeout: webhookTimeout}
+
+// sendStat - функция для фоновой отправки статистики. Если адрес не задан или выключатель открыт, отправка пропускается.
+func sendStat(statURL string, payload []byte) {
+	if statURL == "" {
+		return
+	}
+	if !webhookBreaker.Allow() {
+		config.Logger.Info("отладка: отправка статистики пропущена, сервер статистики недоступен")
+		return
+	}
+
+	go func() {
+		err := sendWithRetry(statURL, payload)
+		webhookBreaker.Record(err)
+		stats.RecordWebhook(err)
+		if err != nil {
+			config.Logger.Error("Ошибка при отправке данных на сервер:", err)
+		}
+	}()
+}
+
+// sendWithRetry - функция для отправки статистики с повторами при ошибках.
+func sendWithRetry(statURL string, payload []byte) error {
+	var err error
+	for attempt := 1; attempt <= webhookAttempts; attempt++ {
+		if err = postStat(statURL, payload); err == nil {
+			return nil
+		}
+		if attempt < webhookAttempts {
+			time.Sleep(time.Duration(attempt) * webhookRetryDelay)
+		}
+	}
+	return err
+}
+
+// postStat - функция для однократной отправки статистики.
+func postStat(statURL string, payload []byte) error {
+	resp, err := webhookClient.Post(statURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("сервер статистики ответил %s", resp.Status)
+	}
+	return nil
+}
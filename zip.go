@@ -0,0 +1,165 @@
+package main
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// zipEntry - файл, попадающий в архив директории.
+type zipEntry struct {
+	path string      // path - путь к файлу на диске.
+	name string      // name - путь внутри архива относительно директории.
+	info fs.FileInfo // info - сведения о файле на момент обхода.
+}
+
+// collectZipEntries - функция для сбора обычных файлов директории root вместе с их общим размером.
+// Символические ссылки не разыменовываются, а поддиректории, закрытые --forbidden, пропускаются.
+// Сбор останавливается, как только общий размер превышает limit (0 - без ограничения).
+func collectZipEntries(r *http.Request, root string, limit int64) ([]zipEntry, int64, error) {
+	var entries []zipEntry
+	var total int64
+	err := walkTree(r, root, func(path string, d fs.DirEntry) error {
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		name, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		entries = append(entries, zipEntry{path: path, name: filepath.ToSlash(name), info: info})
+		total += info.Size()
+		if limit > 0 && total > limit {
+			return errZipTooLarge
+		}
+		return nil
+	})
+	return entries, total, err
+}
+
+// errZipTooLarge - ошибка сбора файлов, общий размер которых превышает --max-zip-bytes.
+var errZipTooLarge = errors.New("размер директории превышает допустимый для архива")
+
+// handleDownloadZip - функция-обработчик для скачивания директории архивом ZIP. Архив пишется прямо
+// в ответ без Content-Length. Общий размер файлов проверяется заранее, чтобы отказать до отправки
+// заголовков; если файлы выросли во время упаковки, соединение обрывается и клиент получает
+// неполный архив вместо тихо обрезанного. Имя архива по умолчанию - имя директории, параметр
+// filename задает другое (после очистки sanitiseFilename).
+func handleDownloadZip(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeJSONError(w, http.StatusMethodNotAllowed, "метод не поддерживается")
+		return
+	}
+
+	root, ok := treeRoot(w, r)
+	if !ok {
+		return
+	}
+
+	entries, _, err := collectZipEntries(r, root, config.MaxZipBytes)
+	if errors.Is(err, errZipTooLarge) {
+		writeJSONError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("%v (--max-zip-bytes %d)", err, config.MaxZipBytes))
+		return
+	}
+	if err != nil {
+		// Клиент отключился или истекло время запроса.
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	name := exportName(root)
+	if filename := sanitiseFilename(r.URL.Query().Get("filename")); filename != "" {
+		name = filename
+	}
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name + ".zip"}))
+
+	logger := requestLogger(r)
+	archive := zip.NewWriter(w)
+	remaining := config.MaxZipBytes
+	for _, entry := range entries {
+		if r.Context().Err() != nil {
+			return
+		}
+		written, err := writeZipEntry(archive, entry, remaining)
+		if err != nil {
+			logger.Error("Ошибка при упаковке", entry.path+":", err)
+			panic(http.ErrAbortHandler)
+		}
+		if config.MaxZipBytes > 0 {
+			remaining -= written
+		}
+	}
+	if err := archive.Close(); err != nil {
+		logger.Error("Ошибка при завершении архива:", err)
+	}
+}
+
+// sanitiseFilename - функция для очистки имени архива, переданного клиентом: удаляются разделители
+// путей, управляющие символы и некорректный UTF-8, а без --allow-unicode-filenames - и символы не из
+// ASCII. Точки и пробелы по краям и расширение .zip отбрасываются, чтобы имя не оказалось скрытым
+// или с двойным расширением. Пустая строка означает, что от имени ничего не осталось.
+func sanitiseFilename(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '/' || r == '\\' || r == utf8.RuneError || unicode.IsControl(r):
+		case r > unicode.MaxASCII && !config.AllowUnicodeFilenames:
+		default:
+			b.WriteRune(r)
+		}
+	}
+	name := strings.Trim(b.String(), ". ")
+	if strings.HasSuffix(strings.ToLower(name), ".zip") {
+		name = strings.TrimRight(name[:len(name)-len(".zip")], ". ")
+	}
+	return name
+}
+
+// writeZipEntry - функция для записи одного файла в архив. Если limit больше нуля и файл
+// к моменту чтения вырос сверх него, возвращается errZipTooLarge.
+func writeZipEntry(archive *zip.Writer, entry zipEntry, limit int64) (int64, error) {
+	file, err := os.Open(entry.path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	header, err := zip.FileInfoHeader(entry.info)
+	if err != nil {
+		return 0, err
+	}
+	header.Name = entry.name
+	header.Method = zip.Deflate
+
+	dst, err := archive.CreateHeader(header)
+	if err != nil {
+		return 0, err
+	}
+
+	var src io.Reader = file
+	if limit > 0 {
+		src = io.LimitReader(file, limit+1)
+	}
+	written, err := io.Copy(dst, src)
+	if err != nil {
+		return written, err
+	}
+	if limit > 0 && written > limit {
+		return written, errZipTooLarge
+	}
+	return written, nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSanitiseFilename(t *testing.T) {
+	saved := config
+	t.Cleanup(func() { config = saved })
+
+	tests := []struct {
+		in      string
+		unicode bool
+		want    string
+	}{
+		{"my-backup-2024", false, "my-backup-2024"},
+		{"my backup 2024", false, "my backup 2024"},
+		{"  spaced  ", false, "spaced"},
+		{"../../etc/passwd", false, "etcpasswd"},
+		{`..\..\windows\system32`, false, "windowssystem32"},
+		{"/abs/path", false, "abspath"},
+		{".hidden", false, "hidden"},
+		{"tab\there\r\nnew\x00line\x7f", false, "tabherenewline"},
+		{"отчет-2024", false, "-2024"},
+		{"отчет-2024", true, "отчет-2024"},
+		{"naïve café", false, "nave caf"},
+		{"naïve café", true, "naïve café"},
+		{"bad\xffutf8", true, "badutf8"},
+		{"backup.zip", false, "backup"},
+		{"backup.ZIP", false, "backup"},
+		{"archive.tar", false, "archive.tar"},
+		{"ирина", false, ""},
+		{"../", false, ""},
+		{"", false, ""},
+	}
+	for _, tt := range tests {
+		config.AllowUnicodeFilenames = tt.unicode
+		if got := sanitiseFilename(tt.in); got != tt.want {
+			t.Errorf("sanitiseFilename(%q) with unicode=%v = %q, want %q", tt.in, tt.unicode, got, tt.want)
+		}
+	}
+}
+
+func TestHandleDownloadZipFilename(t *testing.T) {
+	saved := config
+	t.Cleanup(func() { config = saved })
+	config.Logger = NewTextLogger(io.Discard)
+
+	root := filepath.Join(t.TempDir(), "data")
+	if err := os.Mkdir(root, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		filename string
+		unicode  bool
+		want     string
+	}{
+		{"", false, "attachment; filename=data.zip"},
+		{"my-backup-2024", false, "attachment; filename=my-backup-2024.zip"},
+		{"my backup", false, `attachment; filename="my backup.zip"`},
+		{"../../etc/passwd", false, "attachment; filename=etcpasswd.zip"},
+		{"../", false, "attachment; filename=data.zip"},
+		{"отчет", false, "attachment; filename=data.zip"},
+		{"отчет", true, "attachment; filename*=utf-8''%D0%BE%D1%82%D1%87%D0%B5%D1%82.zip"},
+	}
+	for _, tt := range tests {
+		config.AllowUnicodeFilenames = tt.unicode
+		query := url.Values{"root": {root}}
+		if tt.filename != "" {
+			query.Set("filename", tt.filename)
+		}
+
+		rec := httptest.NewRecorder()
+		handleDownloadZip(rec, httptest.NewRequest(http.MethodGet, "/download-zip?"+query.Encode(), nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("filename %q: status = %d, body %s", tt.filename, rec.Code, rec.Body)
+		}
+		if got := rec.Header().Get("Content-Disposition"); got != tt.want {
+			t.Errorf("filename %q: Content-Disposition = %q, want %q", tt.filename, got, tt.want)
+		}
+	}
+}